@@ -6,4 +6,7 @@ require (
 	github.com/spaceapegames/go-wavefront v1.6.2
 )
 
+// go-wavefront v1.6.2 with Config.HTTPClient, so the provider can send requests with its own http.Client
+replace github.com/spaceapegames/go-wavefront => ./third_party/go-wavefront
+
 go 1.13
@@ -0,0 +1,3 @@
+module github.com/spaceapegames/go-wavefront
+
+go 1.13
@@ -0,0 +1,102 @@
+package wavefront_plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// APIError is an error response from the Wavefront API
+type APIError struct {
+	// The HTTP status code and status line of the response, e.g. 404 and "404 Not Found"
+	StatusCode int
+	Status     string
+	Method     string
+	Path       string
+	// The message from the status of Wavefront's JSON response, or the body when it isn't JSON
+	Message string
+	// The X-Request-ID of the response, for Wavefront support
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s returned %s", e.Method, e.Path, e.Status)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return msg
+}
+
+// apiErrorOf returns the APIError of a request to Wavefront, if the request failed with one
+func apiErrorOf(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+// isNotFound reports whether a request to Wavefront failed because the object does not exist
+func isNotFound(err error) bool {
+	apiErr, ok := apiErrorOf(err)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// wavefrontError returns the error of an API call made for a resource or data source, in the same form for all of
+// them. The APIError is unwrapped from the *url.Error returned by http.Client, which repeats the URL.
+func wavefrontError(action string, kind string, name interface{}, err error) error {
+	if apiErr, ok := apiErrorOf(err); ok {
+		err = apiErr
+	}
+	return fmt.Errorf("error %s Wavefront %s %v. %s", action, kind, name, err)
+}
+
+// apiErrorTransport returns an APIError for every response other than a 200, which go-wavefront would otherwise
+// return as an error with the response body in its message
+type apiErrorTransport struct {
+	next http.RoundTripper
+}
+
+// wavefrontStatus is the status Wavefront includes in the body of its JSON responses
+type wavefrontStatus struct {
+	Status struct {
+		Message string `json:"message"`
+	} `json:"status"`
+	Message string `json:"message"`
+}
+
+func (t *apiErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode == http.StatusOK {
+		return resp, err
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	return nil, newAPIError(req, resp, body)
+}
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+
+	var status wavefrontStatus
+	if err := json.Unmarshal(body, &status); err == nil {
+		apiErr.Message = status.Status.Message
+		if apiErr.Message == "" {
+			apiErr.Message = status.Message
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	return apiErr
+}
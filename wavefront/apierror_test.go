@@ -0,0 +1,121 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestAccWavefrontAlert_DeletedOutOfBand(t *testing.T) {
+	var record wavefront.Alert
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontAlert_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontAlertExists("wavefront_alert.test_alert", &record),
+					func(*terraform.State) error {
+						return testAccProvider.Meta().(*wavefrontClient).client.Alerts().Delete(&record)
+					},
+				),
+				// The refresh removes the alert from state, so it is planned to be created again
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccWavefrontTarget_DeletedOutOfBand(t *testing.T) {
+	var record wavefront.Target
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontTargetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontTarget_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontTargetExists("wavefront_alert_target.test_target", &record),
+					func(*terraform.State) error {
+						return testAccProvider.Meta().(*wavefrontClient).client.Targets().Delete(&record)
+					},
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAPIErrorTransport(t *testing.T) {
+	cases := []struct {
+		status   int
+		header   string
+		body     string
+		expected APIError
+	}{
+		{
+			status: 404,
+			header: "req-1",
+			body:   `{"status":{"result":"ERROR","message":"Alert 123 does not exist","code":404}}`,
+			expected: APIError{StatusCode: 404, Status: "404 Not Found", Method: "GET", Path: "/api/v2/alert/123",
+				Message: "Alert 123 does not exist", RequestID: "req-1"},
+		},
+		{
+			status: 400,
+			body:   `{"message":"invalid query"}`,
+			expected: APIError{StatusCode: 400, Status: "400 Bad Request", Method: "GET", Path: "/api/v2/alert/123",
+				Message: "invalid query"},
+		},
+		{
+			status: 502,
+			body:   "<html>Bad Gateway</html>\n",
+			expected: APIError{StatusCode: 502, Status: "502 Bad Gateway", Method: "GET", Path: "/api/v2/alert/123",
+				Message: "<html>Bad Gateway</html>"},
+		},
+	}
+
+	for _, c := range cases {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c.header != "" {
+				w.Header().Set("X-Request-ID", c.header)
+			}
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.body))
+		}))
+
+		client := &http.Client{Transport: &apiErrorTransport{next: http.DefaultTransport}}
+		_, err := client.Get(s.URL + "/api/v2/alert/123")
+		apiErr, ok := apiErrorOf(err)
+		if !ok {
+			t.Errorf("expected an APIError for a %d, got %v", c.status, err)
+		} else if *apiErr != c.expected {
+			t.Errorf("expected %+v, got %+v", c.expected, *apiErr)
+		}
+		if isNotFound(err) != (c.status == 404) {
+			t.Errorf("expected isNotFound to be %t for a %d", c.status == 404, c.status)
+		}
+		s.Close()
+	}
+}
+
+func TestWavefrontError(t *testing.T) {
+	apiErr := &APIError{StatusCode: 404, Status: "404 Not Found", Method: "GET", Path: "/api/v2/alert/123",
+		Message: "Alert 123 does not exist", RequestID: "req-1"}
+	err := wavefrontError("reading", "Alert", "123", fmt.Errorf("wrapped: %w", apiErr))
+
+	expected := "error reading Wavefront Alert 123. GET /api/v2/alert/123 returned 404 Not Found: " +
+		"Alert 123 does not exist (request ID req-1)"
+	if err.Error() != expected {
+		t.Errorf("expected '%s', got '%s'", expected, err)
+	}
+}
@@ -0,0 +1,232 @@
+package wavefront_plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+const (
+	grantTypeRefreshToken      = "refresh_token"
+	grantTypeClientCredentials = "client_credentials"
+
+	// Access tokens are refreshed this long before they expire, so they don't expire in flight
+	tokenExpiryDelta = 30 * time.Second
+)
+
+// tokenExchangeSchema is the schema of the token_exchange block of the provider and of each of its cluster blocks
+func tokenExchangeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Exchanges a long-lived credential at an OAuth 2 token endpoint for short-lived access tokens",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"token_url": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"grant_type": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      grantTypeRefreshToken,
+					Description:  "refresh_token exchanges the token, client_credentials the client_id and client_secret",
+					ValidateFunc: validateStringIn(grantTypeRefreshToken, grantTypeClientCredentials),
+				},
+				"client_id": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"client_secret": &schema.Schema{
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+				"scopes": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+// exchangesClientCredentials reports whether a token_exchange block exchanges client credentials, which need no
+// token
+func exchangesClientCredentials(exchange []interface{}) bool {
+	if len(exchange) == 0 || exchange[0] == nil {
+		return false
+	}
+	return exchange[0].(map[string]interface{})["grant_type"] == grantTypeClientCredentials
+}
+
+// tokenSource exchanges a long-lived credential at an OAuth 2 token endpoint for short-lived access tokens. With the
+// refresh_token grant the credential is the provider's token, with the client_credentials grant it is the client ID
+// and secret.
+type tokenSource struct {
+	client       *http.Client
+	tokenURL     string
+	grantType    string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	now          func() time.Time
+
+	mu sync.Mutex
+	// The refresh token sent in the next exchange. It starts as the provider's token, and is replaced when the token
+	// endpoint rotates refresh tokens and returns a new one.
+	credential  string
+	accessToken string
+	// Zero when the token endpoint gave no expiry, the token is then only refreshed after a 401
+	expiry time.Time
+}
+
+// tokenResponse is the body of a token endpoint's response, successful or not
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// token returns the current access token, exchanging the credential for a new one if it has expired
+func (s *tokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken != "" && (s.expiry.IsZero() || s.now().Add(tokenExpiryDelta).Before(s.expiry)) {
+		return s.accessToken, nil
+	}
+
+	tr, err := s.exchange(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.accessToken = tr.AccessToken
+	s.expiry = time.Time{}
+	if tr.ExpiresIn > 0 {
+		s.expiry = s.now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	// The old refresh token may no longer be accepted once a new one is issued
+	if s.grantType == grantTypeRefreshToken && tr.RefreshToken != "" {
+		s.credential = tr.RefreshToken
+	}
+	return s.accessToken, nil
+}
+
+// invalidate discards the access token if it is still the current one, so the next call to token refreshes it
+func (s *tokenSource) invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.accessToken == token {
+		s.accessToken = ""
+	}
+}
+
+// exchange requests a new access token. It is called with s.mu held.
+func (s *tokenSource) exchange(ctx context.Context) (*tokenResponse, error) {
+	form := url.Values{"grant_type": {s.grantType}}
+	if s.grantType == grantTypeRefreshToken {
+		form.Set("refresh_token", s.credential)
+	}
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging token at %s. %s", s.tokenURL, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.clientID != "" {
+		req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+	}
+
+	log.Printf("[DEBUG] Exchanging token at %s", s.tokenURL)
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging token at %s. %s", s.tokenURL, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging token at %s. %s", s.tokenURL, err)
+	}
+
+	var tr tokenResponse
+	jsonErr := json.Unmarshal(body, &tr)
+	if resp.StatusCode != http.StatusOK {
+		if jsonErr == nil && tr.Error != "" {
+			return nil, fmt.Errorf("error exchanging token at %s. %s: %s %s",
+				s.tokenURL, resp.Status, tr.Error, tr.ErrorDescription)
+		}
+		return nil, fmt.Errorf("error exchanging token at %s. %s", s.tokenURL, resp.Status)
+	}
+	if jsonErr != nil {
+		return nil, fmt.Errorf("error exchanging token at %s. invalid response %s", s.tokenURL, jsonErr)
+	}
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("error exchanging token at %s. the response has no access_token", s.tokenURL)
+	}
+	return &tr, nil
+}
+
+// authTransport sends each request with an access token from a tokenSource, in place of the token go-wavefront
+// adds. When Wavefront rejects the token with a 401, for example because it was revoked before it expired, the
+// token is refreshed and the request retried once.
+type authTransport struct {
+	next   http.RoundTripper
+	source *tokenSource
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, token, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// The body has been sent, so can only be replayed when it can be recreated
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	log.Printf("[DEBUG] Wavefront rejected the access token for %s %s, refreshing it", req.Method, req.URL.Path)
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	t.source.invalidate(token)
+
+	r := req
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r = req.Clone(req.Context())
+		r.Body = body
+	}
+	resp, _, err = t.send(r)
+	return resp, err
+}
+
+func (t *authTransport) send(req *http.Request) (*http.Response, string, error) {
+	token, err := t.source.token(req.Context())
+	if err != nil {
+		return nil, "", err
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	resp, err := t.next.RoundTrip(r)
+	return resp, token, err
+}
@@ -0,0 +1,261 @@
+package wavefront_plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestAccWavefrontAlert_TokenExchange(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("token exchange is tested against the mock's token endpoint")
+	}
+	var record wavefront.Alert
+	config := testAccCheckWavefrontProvider_tokenExchange(testAccMock.Address()) + testAccCheckWavefrontAlert_basic()
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				// The provider's token is only sent to the token endpoint, Wavefront is sent access tokens
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontAlertExists("wavefront_alert.test_alert", &record),
+					func(*terraform.State) error {
+						testAccMock.mu.Lock()
+						defer testAccMock.mu.Unlock()
+						if testAccMock.exchanges == 0 {
+							return fmt.Errorf("expected the provider's token to be exchanged for an access token")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontProvider_tokenExchange(address string) string {
+	return fmt.Sprintf(`
+provider "wavefront" {
+  token_exchange {
+    token_url = "https://%s/oauth/token"
+  }
+}
+`, address)
+}
+
+// A stand-in for a token endpoint, issuing access-token-1, access-token-2... which expire after expiresIn seconds.
+// When rotate is set it also issues refresh-token-1, refresh-token-2... and only accepts the last one issued.
+type tokenTestServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	expiresIn int
+	rotate    bool
+	refresh   string
+	forms     []map[string]string
+	auth      []string
+}
+
+func newTokenTestServer(expiresIn int) *tokenTestServer {
+	s := &tokenTestServer{expiresIn: expiresIn, refresh: "refresh-credential"}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		form := map[string]string{}
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		s.forms = append(s.forms, form)
+		s.auth = append(s.auth, r.Header.Get("Authorization"))
+
+		if form["refresh_token"] == "revoked" || (s.rotate && form["refresh_token"] != s.refresh) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"the refresh token was revoked"}`))
+			return
+		}
+		response := map[string]interface{}{
+			"access_token": fmt.Sprintf("access-token-%d", len(s.forms)),
+			"expires_in":   s.expiresIn,
+		}
+		if s.rotate {
+			s.refresh = fmt.Sprintf("refresh-token-%d", len(s.forms))
+			response["refresh_token"] = s.refresh
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	return s
+}
+
+func (s *tokenTestServer) exchanges() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.forms)
+}
+
+func testTokenSource(s *tokenTestServer, now *time.Time) *tokenSource {
+	return &tokenSource{
+		client:     s.Client(),
+		tokenURL:   s.URL,
+		grantType:  grantTypeRefreshToken,
+		credential: "refresh-credential",
+		now:        func() time.Time { return *now },
+	}
+}
+
+func TestTokenSource_refreshesOnExpiry(t *testing.T) {
+	s := newTokenTestServer(300)
+	defer s.Close()
+	now := time.Unix(1570000000, 0)
+	source := testTokenSource(s, &now)
+
+	// The time since the last call, and the token expected
+	expected := []struct {
+		after time.Duration
+		token string
+	}{
+		{0, "access-token-1"},
+		{time.Minute, "access-token-1"},
+		// Refreshed shortly before it expires
+		{3*time.Minute + 40*time.Second, "access-token-2"},
+		{4 * time.Minute, "access-token-2"},
+	}
+
+	for _, e := range expected {
+		now = now.Add(e.after)
+		token, err := source.token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != e.token {
+			t.Errorf("expected %s after %s, got %s", e.token, e.after, token)
+		}
+	}
+
+	if s.forms[0]["grant_type"] != "refresh_token" || s.forms[0]["refresh_token"] != "refresh-credential" {
+		t.Errorf("expected the refresh credential to be exchanged, got %v", s.forms[0])
+	}
+}
+
+func TestTokenSource_rotatesRefreshToken(t *testing.T) {
+	s := newTokenTestServer(300)
+	s.rotate = true
+	defer s.Close()
+	now := time.Unix(1570000000, 0)
+	source := testTokenSource(s, &now)
+
+	for i := 1; i <= 3; i++ {
+		token, err := source.token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := fmt.Sprintf("access-token-%d", i); token != expected {
+			t.Errorf("expected %s, got %s", expected, token)
+		}
+		now = now.Add(5 * time.Minute)
+	}
+
+	expected := []string{"refresh-credential", "refresh-token-1", "refresh-token-2"}
+	for i, form := range s.forms {
+		if form["refresh_token"] != expected[i] {
+			t.Errorf("expected exchange %d to send refresh token %s, got %s", i+1, expected[i], form["refresh_token"])
+		}
+	}
+	if source.credential != "refresh-token-3" {
+		t.Errorf("expected the last refresh token issued to be kept, got %s", source.credential)
+	}
+}
+
+func TestTokenSource_clientCredentials(t *testing.T) {
+	s := newTokenTestServer(0)
+	defer s.Close()
+	now := time.Now()
+	source := testTokenSource(s, &now)
+	source.grantType = grantTypeClientCredentials
+	source.clientID = "service-account"
+	source.clientSecret = "secret"
+	source.scopes = []string{"alerts", "dashboards"}
+
+	for i := 0; i < 2; i++ {
+		if _, err := source.token(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s.exchanges() != 1 {
+		t.Errorf("expected a token without an expiry to be reused, got %d exchanges", s.exchanges())
+	}
+	expected := map[string]string{"grant_type": "client_credentials", "scope": "alerts dashboards"}
+	if fmt.Sprint(s.forms[0]) != fmt.Sprint(expected) {
+		t.Errorf("expected the form %v, got %v", expected, s.forms[0])
+	}
+	if s.auth[0] != "Basic c2VydmljZS1hY2NvdW50OnNlY3JldA==" {
+		t.Errorf("expected the client credentials to be sent with basic auth, got %q", s.auth[0])
+	}
+}
+
+func TestTokenSource_error(t *testing.T) {
+	s := newTokenTestServer(300)
+	defer s.Close()
+	now := time.Now()
+	source := testTokenSource(s, &now)
+	source.credential = "revoked"
+
+	_, err := source.token(context.Background())
+	expected := fmt.Sprintf("error exchanging token at %s. 400 Bad Request: invalid_grant the refresh token was revoked", s.URL)
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
+
+func TestAuthTransport_refreshesOnUnauthorized(t *testing.T) {
+	tokens := newTokenTestServer(300)
+	defer tokens.Close()
+	now := time.Now()
+	source := testTokenSource(tokens, &now)
+
+	var mu sync.Mutex
+	var requests []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Header.Get("Authorization")+" "+string(body))
+		// The first access token has been revoked
+		if r.Header.Get("Authorization") != "Bearer access-token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer api.Close()
+
+	client := &http.Client{Transport: &authTransport{next: http.DefaultTransport, source: source}}
+	req, _ := http.NewRequest(http.MethodPut, api.URL, strings.NewReader(`{"name":"alert"}`))
+	req.Header.Set("Authorization", "Bearer refresh-credential")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("expected the request to succeed with a refreshed token, got %d", resp.StatusCode)
+	}
+	expected := []string{`Bearer access-token-1 {"name":"alert"}`, `Bearer access-token-2 {"name":"alert"}`}
+	if fmt.Sprint(requests) != fmt.Sprint(expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+}
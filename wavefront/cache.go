@@ -0,0 +1,134 @@
+package wavefront_plugin
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// The number of objects of a type read with a GET before the rest are prefetched, so that a run reading only a few
+// objects doesn't search every object of the tenant
+const readCachePrefetchAfter = 5
+
+// readCache serves the reads of alerts, alert targets and dashboards from the search API. Once more than a few
+// objects of a type have been read, every object of the type is prefetched in pages, so refreshing many resources
+// takes a handful of searches rather than a GET per resource. Objects missing from the prefetched results, and those
+// written since, are read with a GET. The cache lives as long as the provider meta, i.e. for a single plan, refresh or
+// apply.
+type readCache struct {
+	pageSize int
+	// The number of reads of a type made with a GET before it is prefetched
+	prefetchAfter int
+
+	mu    sync.Mutex
+	types map[string]*readCacheType
+}
+
+// readCacheType holds the prefetched objects of one search type, keyed by ID
+type readCacheType struct {
+	reads int
+	once  sync.Once
+	items map[string]json.RawMessage
+	// The IDs of objects written since the prefetch
+	evicted map[string]bool
+}
+
+func newReadCache(pageSize int) *readCache {
+	return &readCache{
+		pageSize:      pageSize,
+		prefetchAfter: readCachePrefetchAfter,
+		types:         map[string]*readCacheType{},
+	}
+}
+
+// read reads the object of the search type with the ID into v from the prefetched results, and calls get to read it
+// from Wavefront when it is not among them. The first read of a type after prefetchAfter reads prefetches its objects
+// with client. A nil readCache always calls get.
+func (c *readCache) read(client *wavefront.Client, searchType string, id string, v interface{}, get func() error) error {
+	if c == nil {
+		return get()
+	}
+
+	t := c.typeOf(searchType)
+	c.mu.Lock()
+	t.reads++
+	prefetch := t.reads > c.prefetchAfter
+	c.mu.Unlock()
+	if !prefetch {
+		return get()
+	}
+	t.once.Do(func() {
+		items, err := c.prefetch(client, searchType)
+		if err != nil {
+			log.Printf("[WARN] Error prefetching Wavefront %ss, reading them one at a time. %s", searchType, err)
+		}
+		c.mu.Lock()
+		t.items = items
+		c.mu.Unlock()
+	})
+
+	c.mu.Lock()
+	item, ok := t.items[id]
+	if t.evicted[id] {
+		ok = false
+	}
+	c.mu.Unlock()
+	if !ok {
+		return get()
+	}
+	if err := json.Unmarshal(item, v); err != nil {
+		log.Printf("[WARN] Error decoding the prefetched Wavefront %s %s, reading it again. %s", searchType, id, err)
+		return get()
+	}
+	return nil
+}
+
+// evict stops the object of the search type with the ID being read from the prefetched results, once it has been
+// created, updated or deleted
+func (c *readCache) evict(searchType string, id string) {
+	if c == nil {
+		return
+	}
+	t := c.typeOf(searchType)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t.evicted[id] = true
+}
+
+func (c *readCache) typeOf(searchType string) *readCacheType {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.types[searchType]
+	if !ok {
+		t = &readCacheType{evicted: map[string]bool{}}
+		c.types[searchType] = t
+	}
+	return t
+}
+
+// prefetch searches for every object of the search type, returning them keyed by ID
+func (c *readCache) prefetch(client *wavefront.Client, searchType string) (map[string]json.RawMessage, error) {
+	items := map[string]json.RawMessage{}
+	err := searchPages(client, searchType, nil, c.pageSize, func(results json.RawMessage) (int, error) {
+		var page []json.RawMessage
+		if err := json.Unmarshal(results, &page); err != nil {
+			return 0, err
+		}
+		for _, item := range page {
+			var obj struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(item, &obj); err == nil && obj.ID != "" {
+				items[obj.ID] = item
+			}
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[DEBUG] Prefetched %d Wavefront %ss", len(items), searchType)
+	return items, nil
+}
@@ -0,0 +1,200 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestAccWavefrontAlert_ReadCache(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("the requests made by a refresh are counted by the mock Wavefront API")
+	}
+	var gets, searches int
+	count := func() {
+		gets = testAccMock.countRequests("GET", "/api/v2/alert/")
+		searches = testAccMock.countRequests("POST", "/api/v2/search/alert")
+	}
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontAlert_count(2),
+			},
+			{
+				// A few alerts are read one at a time rather than searching every alert of the tenant
+				PreConfig: count,
+				Config:    testAccCheckWavefrontAlert_count(2),
+				Check: func(*terraform.State) error {
+					if testAccMock.countRequests("POST", "/api/v2/search/alert") != searches {
+						return fmt.Errorf("expected a few alerts not to be prefetched with a search")
+					}
+					if testAccMock.countRequests("GET", "/api/v2/alert/") == gets {
+						return fmt.Errorf("expected a few alerts to be read with a GET")
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccCheckWavefrontAlert_count(readCachePrefetchAfter * 2),
+			},
+			{
+				// Many alerts are refreshed from a search rather than read one at a time
+				PreConfig: count,
+				Config:    testAccCheckWavefrontAlert_count(readCachePrefetchAfter * 2),
+				Check: func(*terraform.State) error {
+					if testAccMock.countRequests("POST", "/api/v2/search/alert") == searches {
+						return fmt.Errorf("expected the alerts to be prefetched with a search")
+					}
+					if n := testAccMock.countRequests("GET", "/api/v2/alert/") - gets; n >= readCachePrefetchAfter*2 {
+						return fmt.Errorf("expected most alerts to be read from the cache, got %d GETs", n)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontAlert_count(count int) string {
+	return fmt.Sprintf(`
+resource "wavefront_alert" "test_alert" {
+  count = %d
+  name = "Terraform Test Alert ${count.index}"
+  target = "test@example.com"
+  condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
+  minutes = 5
+  severity = "WARN"
+  tags = ["terraform"]
+}
+`, count)
+}
+
+func TestReadCache(t *testing.T) {
+	m := newMockWavefront()
+	defer m.Close()
+	client, err := wavefront.NewClient(&wavefront.Config{
+		Address:       m.Address(),
+		Token:         mockWavefrontToken,
+		SkipTLSVerify: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	for i := 0; i < 5; i++ {
+		a := &wavefront.Alert{Name: fmt.Sprintf("alert %d", i), Condition: "ts(cpu) > 80", Minutes: 5, Severity: "WARN"}
+		if err := client.Alerts().Create(a); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, *a.ID)
+	}
+
+	cache := newReadCache(2)
+	cache.prefetchAfter = 0
+	get := func(a *wavefront.Alert) func() error {
+		return func() error {
+			return client.Alerts().Get(a)
+		}
+	}
+
+	for i, id := range ids {
+		a := wavefront.Alert{ID: &id}
+		if err := cache.read(client, "alert", id, &a, get(&a)); err != nil {
+			t.Fatal(err)
+		}
+		if a.Name != fmt.Sprintf("alert %d", i) {
+			t.Errorf("expected alert %d to be read from the cache, got %s", i, a.Name)
+		}
+	}
+	if searches := m.countRequests("POST", "/api/v2/search/alert"); searches != 3 {
+		t.Errorf("expected the alerts to be prefetched in 3 pages, got %d searches", searches)
+	}
+	if gets := m.countRequests("GET", "/api/v2/alert/"); gets != 0 {
+		t.Errorf("expected no alerts to be read with a GET, got %d", gets)
+	}
+
+	// Objects missing from the cache, and those evicted from it, are read with a GET
+	missing := "missing"
+	a := wavefront.Alert{ID: &missing}
+	if err := cache.read(client, "alert", missing, &a, get(&a)); err == nil {
+		t.Errorf("expected a missing alert to be read with a GET and not be found")
+	}
+	cache.evict("alert", ids[0])
+	a = wavefront.Alert{ID: &ids[0]}
+	if err := cache.read(client, "alert", ids[0], &a, get(&a)); err != nil {
+		t.Fatal(err)
+	}
+	if gets := m.countRequests("GET", "/api/v2/alert/"); gets != 2 {
+		t.Errorf("expected 2 alerts to be read with a GET, got %d", gets)
+	}
+	if searches := m.countRequests("POST", "/api/v2/search/alert"); searches != 3 {
+		t.Errorf("expected the alerts to be prefetched once, got %d searches", searches)
+	}
+}
+
+func TestReadCache_prefetchAfter(t *testing.T) {
+	m := newMockWavefront()
+	defer m.Close()
+	client, err := wavefront.NewClient(&wavefront.Config{
+		Address:       m.Address(),
+		Token:         mockWavefrontToken,
+		SkipTLSVerify: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []string{}
+	for i := 0; i < 4; i++ {
+		a := &wavefront.Alert{Name: fmt.Sprintf("alert %d", i), Condition: "ts(cpu) > 80", Minutes: 5, Severity: "WARN"}
+		if err := client.Alerts().Create(a); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, *a.ID)
+	}
+
+	cache := newReadCache(10)
+	cache.prefetchAfter = 2
+	// The number of GETs and searches expected after each read
+	expected := []struct {
+		gets     int
+		searches int
+	}{
+		{1, 0},
+		{2, 0},
+		{2, 1},
+		{2, 1},
+	}
+	for i, id := range ids {
+		a := wavefront.Alert{ID: &id}
+		if err := cache.read(client, "alert", id, &a, func() error { return client.Alerts().Get(&a) }); err != nil {
+			t.Fatal(err)
+		}
+		gets, searches := m.countRequests("GET", "/api/v2/alert/"), m.countRequests("POST", "/api/v2/search/alert")
+		if gets != expected[i].gets || searches != expected[i].searches {
+			t.Errorf("expected %d GETs and %d searches after reading %d alerts, got %d and %d",
+				expected[i].gets, expected[i].searches, i+1, gets, searches)
+		}
+	}
+}
+
+func TestReadCache_disabled(t *testing.T) {
+	var cache *readCache
+	called := false
+	cache.read(nil, "alert", "1", &wavefront.Alert{}, func() error {
+		called = true
+		return nil
+	})
+	if !called {
+		t.Errorf("expected a nil cache to read every object with get")
+	}
+	cache.evict("alert", "1")
+}
@@ -0,0 +1,183 @@
+package wavefront_plugin
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// newWavefrontClient creates the provider meta shared by every resource and data source. The go-wavefront client is
+// given an http.Client using the provider's proxy, TLS and timeout settings, wrapped with the retry behaviour and
+// request limits configured on the provider. Access tokens are exchanged for config's token when a token_exchange
+// block, of the provider or of a cluster, is given.
+func newWavefrontClient(d *schema.ResourceData, config *wavefront.Config, exchange []interface{}) (*wavefrontClient, error) {
+	var transport http.RoundTripper
+	transport, err := newTransport(d)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure Wavefront Client %s", err)
+	}
+	transport = newTraceTransport(transport)
+	if timeout := secondsToDuration(d.Get("request_timeout").(int)); timeout > 0 {
+		transport = &timeoutTransport{next: transport, timeout: timeout}
+	}
+
+	source, err := newTokenSource(exchange, config.Token, transport)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure Wavefront Client %s", err)
+	}
+	if source != nil {
+		// The credential is only sent to the token endpoint, never to Wavefront
+		config.Token = ""
+		transport = &authTransport{next: transport, source: source}
+	}
+
+	// Each retry is a separate request, so the limits are applied beneath the retries. Every cluster has its own
+	// API quota, so each gets its own limiter.
+	limiter := newRequestLimiter(d.Get("max_concurrent_requests").(int), d.Get("requests_per_second").(float64))
+	config.HTTPClient = &http.Client{
+		Transport: &apiErrorTransport{
+			next: &retryTransport{
+				next:       &limitTransport{next: transport, limiter: limiter},
+				maxRetries: d.Get("max_retries").(int),
+				minWait:    secondsToDuration(d.Get("retry_min_wait").(int)),
+				maxWait:    secondsToDuration(d.Get("retry_max_wait").(int)),
+			},
+		},
+	}
+	wFClient, err := wavefront.NewClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to configure Wavefront Client %s", err)
+	}
+
+	var cache *readCache
+	if d.Get("read_cache").(bool) {
+		cache = newReadCache(d.Get("search_page_size").(int))
+	}
+
+	return &wavefrontClient{
+		client:         *wFClient,
+		limiter:        limiter,
+		defaultTags:    setStrings(d.Get("default_tags.0.tags")),
+		cache:          cache,
+		searchPageSize: d.Get("search_page_size").(int),
+	}, nil
+}
+
+// newTransport creates the transport requests are sent to Wavefront with. go-wavefront only supports a proxy or
+// skipping TLS verification, one replacing the other, so the transport is built here instead.
+func newTransport(d *schema.ResourceData) (*http.Transport, error) {
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		// HTTP/2 doesn't play nicely with nginx, setting TLSNextProto to an empty, non-nil map disables it
+		TLSNextProto:    map[string]func(authority string, c *tls.Conn) http.RoundTripper{},
+		TLSClientConfig: &tls.Config{},
+	}
+
+	if proxy := d.Get("http_proxy").(string); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid http_proxy %q", proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if caFile := d.Get("ca_cert_file").(string); caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_cert_file. %s", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in ca_cert_file %s", caFile)
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	certFile, keyFile := d.Get("client_cert_file").(string), d.Get("client_key_file").(string)
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("client_cert_file and client_key_file must be set together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate. %s", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig.InsecureSkipVerify = d.Get("insecure_skip_verify").(bool)
+
+	return transport, nil
+}
+
+// newTokenSource returns the tokenSource configured by a token_exchange block, or nil when access tokens are not
+// exchanged. The token endpoint is called through transport, with the same proxy, TLS settings and timeout as
+// Wavefront.
+func newTokenSource(v []interface{}, credential string, transport http.RoundTripper) (*tokenSource, error) {
+	if len(v) == 0 || v[0] == nil {
+		return nil, nil
+	}
+	exchange := v[0].(map[string]interface{})
+	if exchange["grant_type"] == grantTypeClientCredentials && (exchange["client_id"] == "" || exchange["client_secret"] == "") {
+		return nil, fmt.Errorf("token_exchange client_id and client_secret must be set for the client_credentials grant")
+	}
+
+	scopes := []string{}
+	for _, scope := range exchange["scopes"].([]interface{}) {
+		scopes = append(scopes, scope.(string))
+	}
+	return &tokenSource{
+		client:       &http.Client{Transport: transport},
+		tokenURL:     exchange["token_url"].(string),
+		grantType:    exchange["grant_type"].(string),
+		credential:   credential,
+		clientID:     exchange["client_id"].(string),
+		clientSecret: exchange["client_secret"].(string),
+		scopes:       scopes,
+		now:          time.Now,
+	}, nil
+}
+
+// timeoutTransport limits the time each request may take, including reading the response body. Unlike
+// http.Client.Timeout it applies to each attempt rather than to all of the retries together.
+type timeoutTransport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelReadCloser cancels the context of a request once its response body has been closed
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
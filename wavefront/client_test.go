@@ -0,0 +1,186 @@
+package wavefront_plugin
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testProviderData returns the provider's settings with an address and, unless raw sets another source of the token,
+// a token
+func testProviderData(t *testing.T, raw map[string]interface{}) *schema.ResourceData {
+	raw["address"] = "example.wavefront.com"
+	_, file := raw["token_file"]
+	_, command := raw["token_command"]
+	if _, ok := raw["token"]; !ok && !file && !command {
+		raw["token"] = "token"
+	}
+	return schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, raw)
+}
+
+// writeClientCertificate writes a self-signed client certificate and its key to PEM files in dir
+func writeClientCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return certFile, keyFile
+}
+
+func TestNewTransport_clientCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavefront-client-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeClientCertificate(t, dir)
+
+	var presented int
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented = len(r.TLS.PeerCertificates)
+	}))
+	s.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	s.StartTLS()
+	defer s.Close()
+
+	transport, err := newTransport(testProviderData(t, map[string]interface{}{
+		"client_cert_file":     certFile,
+		"client_key_file":      keyFile,
+		"insecure_skip_verify": true,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if presented != 1 {
+		t.Errorf("expected the client certificate to be presented, got %d certificates", presented)
+	}
+}
+
+func TestNewTransport_caCertFile(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	f, err := ioutil.TempFile("", "wavefront-ca-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	f.Close()
+
+	transport, err := newTransport(testProviderData(t, map[string]interface{}{"ca_cert_file": f.Name()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Get(s.URL)
+	if err != nil {
+		t.Fatalf("expected the certificate to be trusted, got %s", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewTransport_proxy(t *testing.T) {
+	transport, err := newTransport(testProviderData(t, map[string]interface{}{"http_proxy": "http://proxy.internal:3128"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://example.wavefront.com/api/v2/alert", nil)
+	proxy, err := transport.Proxy(req)
+	if err != nil || proxy == nil || proxy.String() != "http://proxy.internal:3128" {
+		t.Errorf("expected requests to be sent through http://proxy.internal:3128, got %v %v", proxy, err)
+	}
+}
+
+func TestNewTransport_invalid(t *testing.T) {
+	notPEM, err := ioutil.TempFile("", "wavefront-ca-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(notPEM.Name())
+	notPEM.WriteString("not a certificate")
+	notPEM.Close()
+
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"http_proxy": "proxy.internal:3128"}, `invalid http_proxy "proxy.internal:3128"`},
+		{map[string]interface{}{"ca_cert_file": notPEM.Name()}, "no PEM certificates found in ca_cert_file"},
+		{map[string]interface{}{"ca_cert_file": "/does/not/exist.pem"}, "error reading ca_cert_file"},
+		{map[string]interface{}{"client_cert_file": "client.pem"}, "client_cert_file and client_key_file must be set together"},
+		{map[string]interface{}{"client_cert_file": "client.pem", "client_key_file": "key.pem"}, "error loading client certificate"},
+	}
+
+	for _, c := range cases {
+		_, err := newTransport(testProviderData(t, c.raw))
+		if err == nil || !strings.Contains(err.Error(), c.expected) {
+			t.Errorf("expected an error containing %q for %v, got %v", c.expected, c.raw, err)
+		}
+	}
+}
+
+func TestTimeoutTransport(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(500 * time.Millisecond)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer s.Close()
+
+	client := &http.Client{Transport: &timeoutTransport{next: http.DefaultTransport, timeout: 50 * time.Millisecond}}
+
+	resp, err := client.Get(s.URL + "/fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "ok" {
+		t.Errorf("expected the response body to be read within the timeout, got %q %v", body, err)
+	}
+
+	if _, err := client.Get(s.URL + "/slow"); err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("expected the slow request to time out, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// clusterSchema is the schema of the provider's cluster blocks, each naming a Wavefront cluster with its own
+// address and credentials
+func clusterSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Description: "A named Wavefront cluster, selected by the cluster argument of a resource or data source. " +
+			"Clusters share the provider's proxy, TLS, retry and default_tags settings. Each cluster has its own " +
+			"max_concurrent_requests and requests_per_second limits, of the sizes set on the provider.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"address": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"token": &schema.Schema{
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+				"token_file": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"token_command": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"token_exchange": tokenExchangeSchema(),
+			},
+		},
+	}
+}
+
+// clusterArgumentSchema is the cluster argument of every resource and data source. Moving a resource to another
+// cluster creates it there and deletes it from the old one.
+func clusterArgumentSchema(forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    forceNew,
+		Description: "The name of a cluster block of the provider, the provider's address is used when not set",
+	}
+}
+
+// configureClusters creates a client for each of the provider's cluster blocks, keyed by the cluster's name
+func configureClusters(d *schema.ResourceData) (map[string]*wavefrontClient, error) {
+	clusters := map[string]*wavefrontClient{}
+	for _, v := range d.Get("cluster").([]interface{}) {
+		cluster := v.(map[string]interface{})
+		name := cluster["name"].(string)
+		if _, ok := clusters[name]; ok {
+			return nil, fmt.Errorf("cluster %s is defined more than once", name)
+		}
+
+		token, err := tokenFromSettings(cluster["token_command"].([]interface{}), cluster["token_file"].(string),
+			cluster["token"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %s", name, err)
+		}
+		exchange := cluster["token_exchange"].([]interface{})
+		if token == "" && !exchangesClientCredentials(exchange) {
+			return nil, fmt.Errorf("cluster %s: one of token, token_file or token_command must be set", name)
+		}
+
+		config := &wavefront.Config{
+			Address:       cluster["address"].(string),
+			Token:         token,
+			HttpProxy:     d.Get("http_proxy").(string),
+			SkipTLSVerify: d.Get("insecure_skip_verify").(bool),
+		}
+		// The provider's token_exchange is only used with its own address, a cluster sets its own
+		client, err := newWavefrontClient(d, config, exchange)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %s", name, err)
+		}
+		clusters[name] = client
+	}
+	return clusters, nil
+}
+
+// clientFor returns the client of the cluster named by the cluster argument of a resource or data source, or the
+// client of the provider's address when the argument is not set
+func clientFor(d interface{ Get(string) interface{} }, m interface{}) (*wavefrontClient, error) {
+	c := m.(*wavefrontClient)
+	name, _ := d.Get("cluster").(string)
+	if name == "" {
+		if c.address == "" {
+			return nil, fmt.Errorf("cluster must be set as the provider has no address, configured clusters are %s",
+				c.clusterNames())
+		}
+		return c, nil
+	}
+
+	cluster, ok := c.clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s is not configured on the provider, configured clusters are %s", name,
+			c.clusterNames())
+	}
+	return cluster, nil
+}
+
+func (c *wavefrontClient) clusterNames() string {
+	if len(c.clusters) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(c.clusters))
+	for name := range c.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// importStateWithCluster imports a resource by its ID, or by <cluster>/<ID> to import it from a named cluster
+func importStateWithCluster(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if i := strings.Index(d.Id(), "/"); i > 0 {
+		d.Set("cluster", d.Id()[:i])
+		d.SetId(d.Id()[i+1:])
+	}
+	if _, err := clientFor(d, m); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
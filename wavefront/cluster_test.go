@@ -0,0 +1,245 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testGetter stands in for the ResourceData of a resource or data source
+type testGetter map[string]interface{}
+
+func (g testGetter) Get(key string) interface{} {
+	return g[key]
+}
+
+func TestClientFor(t *testing.T) {
+	staging := &wavefrontClient{}
+	meta := &wavefrontClient{address: "prod.wavefront.com", clusters: map[string]*wavefrontClient{"staging": staging}}
+
+	if c, err := clientFor(testGetter{"cluster": ""}, meta); err != nil || c != meta {
+		t.Errorf("expected the provider's client without a cluster, got %v %v", c, err)
+	}
+	if c, err := clientFor(testGetter{"cluster": "staging"}, meta); err != nil || c != staging {
+		t.Errorf("expected the staging client, got %v %v", c, err)
+	}
+
+	_, err := clientFor(testGetter{"cluster": "dev"}, meta)
+	if err == nil || !strings.Contains(err.Error(), "cluster dev is not configured on the provider, configured clusters are staging") {
+		t.Errorf("expected an unknown cluster error, got %v", err)
+	}
+
+	_, err = clientFor(testGetter{"cluster": ""}, &wavefrontClient{clusters: meta.clusters})
+	if err == nil || !strings.Contains(err.Error(), "cluster must be set as the provider has no address") {
+		t.Errorf("expected an error without an address, got %v", err)
+	}
+}
+
+func TestProviderConfigure_clusterToken(t *testing.T) {
+	_, err := configureClusters(testProviderData(t, map[string]interface{}{
+		"cluster": []interface{}{
+			map[string]interface{}{"name": "staging", "address": "staging.wavefront.com"},
+		},
+	}))
+	if err == nil || err.Error() != "cluster staging: one of token, token_file or token_command must be set" {
+		t.Errorf("expected a missing token error, got %v", err)
+	}
+}
+
+func TestProviderConfigure_clusterTokenExchange(t *testing.T) {
+	meta, err := providerConfigure(testProviderData(t, map[string]interface{}{
+		"max_concurrent_requests": 2,
+		"cluster": []interface{}{
+			map[string]interface{}{
+				"name":    "staging",
+				"address": "staging.wavefront.com",
+				"token_exchange": []interface{}{
+					map[string]interface{}{
+						"token_url":     "https://login.example.com/oauth/token",
+						"grant_type":    grantTypeClientCredentials,
+						"client_id":     "service-account",
+						"client_secret": "secret",
+					},
+				},
+			},
+		},
+	}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := meta.(*wavefrontClient)
+	staging := c.clusters["staging"]
+	if _, ok := staging.client.Config.HTTPClient.Transport.(*apiErrorTransport).next.(*retryTransport).next.(*limitTransport).next.(*authTransport); !ok {
+		t.Errorf("expected the staging cluster to exchange its client credentials for access tokens")
+	}
+	if _, ok := c.client.Config.HTTPClient.Transport.(*apiErrorTransport).next.(*retryTransport).next.(*limitTransport).next.(*authTransport); ok {
+		t.Errorf("expected the provider's address not to use the staging cluster's token_exchange")
+	}
+	if staging.limiter == c.limiter || cap(staging.limiter.slots) != 2 {
+		t.Errorf("expected the staging cluster to have its own limiter of 2 requests")
+	}
+}
+
+func TestAccWavefrontAlert_ClusterTokenExchange(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("the cluster test runs against the mock Wavefront API only")
+	}
+	staging := newMockWavefront()
+	defer staging.Close()
+	staging.nextID = 5000
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontClusterAlertsDestroyed(staging),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontAlert_clusterTokenExchange(staging.Address()),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontMockHasAlert(staging, "wavefront_alert.staging", true),
+					func(*terraform.State) error {
+						staging.mu.Lock()
+						defer staging.mu.Unlock()
+						if staging.exchanges == 0 {
+							return fmt.Errorf("expected the staging cluster's token to be exchanged for an access token")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontAlert_Cluster(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("the cluster test runs against the mock Wavefront API only")
+	}
+	staging := newMockWavefront()
+	defer staging.Close()
+	// Keep the IDs of the two clusters apart
+	staging.nextID = 5000
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontClusterAlertsDestroyed(staging),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontAlert_cluster(staging.Address()),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontMockHasAlert(testAccMock, "wavefront_alert.prod", true),
+					testAccCheckWavefrontMockHasAlert(staging, "wavefront_alert.staging", true),
+					testAccCheckWavefrontMockHasAlert(testAccMock, "wavefront_alert.staging", false),
+					resource.TestCheckResourceAttr("wavefront_alert.staging", "cluster", "staging"),
+				),
+			},
+			{
+				// The cluster block of the provider is needed to import from the cluster
+				Config:            testAccCheckWavefrontAlert_cluster(staging.Address()),
+				ResourceName:      "wavefront_alert.staging",
+				ImportState:       true,
+				ImportStateIdFunc: testAccWavefrontClusterImportID("staging", "wavefront_alert.staging"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontMockHasAlert(m *mockWavefront, name string, expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+		if m.exists("alert", rs.Primary.ID) != expected {
+			return fmt.Errorf("expected %s to exist on %s: %t", name, m.Address(), expected)
+		}
+		return nil
+	}
+}
+
+func testAccCheckWavefrontClusterAlertsDestroyed(staging *mockWavefront) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type == "wavefront_alert" && (staging.exists("alert", rs.Primary.ID) || testAccMock.exists("alert", rs.Primary.ID)) {
+				return fmt.Errorf("Alert %s still exists", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccWavefrontClusterImportID(cluster string, name string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", name)
+		}
+		return cluster + "/" + rs.Primary.ID, nil
+	}
+}
+
+func testAccCheckWavefrontAlert_cluster(stagingAddress string) string {
+	return fmt.Sprintf(`
+provider "wavefront" {
+  cluster {
+    name = "staging"
+    address = "%s"
+    token = "%s"
+  }
+}
+
+locals {
+  condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
+}
+
+resource "wavefront_alert" "prod" {
+  name = "Terraform Test Alert"
+  target = "test@example.com"
+  condition = local.condition
+  minutes = 5
+  severity = "WARN"
+  tags = ["terraform"]
+}
+
+resource "wavefront_alert" "staging" {
+  cluster = "staging"
+  name = "Terraform Test Alert"
+  target = "test@example.com"
+  condition = local.condition
+  minutes = 5
+  severity = "WARN"
+  tags = ["terraform"]
+}
+`, stagingAddress, mockWavefrontToken)
+}
+
+func testAccCheckWavefrontAlert_clusterTokenExchange(stagingAddress string) string {
+	return fmt.Sprintf(`
+provider "wavefront" {
+  cluster {
+    name = "staging"
+    address = "%s"
+    token = "%s"
+    token_exchange {
+      token_url = "https://%s/oauth/token"
+    }
+  }
+}
+
+resource "wavefront_alert" "staging" {
+  cluster = "staging"
+  name = "Terraform Test Alert"
+  target = "test@example.com"
+  condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
+  minutes = 5
+  severity = "WARN"
+  tags = ["terraform"]
+}
+`, stagingAddress, mockWavefrontToken, stagingAddress)
+}
@@ -0,0 +1,79 @@
+package wavefront_plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// The default timeout of each operation on a resource, covering every request it makes including retries
+const defaultOperationTimeout = 10 * time.Minute
+
+// resourceTimeouts are the timeouts of a resource's create, read, update and delete, set with a timeouts block
+func resourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultOperationTimeout),
+		Read:   schema.DefaultTimeout(defaultOperationTimeout),
+		Update: schema.DefaultTimeout(defaultOperationTimeout),
+		Delete: schema.DefaultTimeout(defaultOperationTimeout),
+	}
+}
+
+// clientForOperation returns the client of a resource or data source's cluster for a single Terraform operation,
+// the timeout naming the operation's entry in the timeouts block. The operation's requests are cancelled when
+// Terraform is interrupted or the timeout passes. The returned CancelFunc must be called once the operation is done.
+func clientForOperation(d *schema.ResourceData, m interface{}, timeout string) (*wavefrontClient, context.CancelFunc, error) {
+	c, err := clientFor(d, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	if c.stopContext != nil {
+		ctx = c.stopContext()
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout(timeout))
+	op, err := c.withContext(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return op, cancel, nil
+}
+
+// withContext returns a copy of the client whose requests are sent with ctx. go-wavefront creates its requests without
+// a context, so the copy's wavefront.Client is given an http.Client which adds ctx to each of them. The request
+// limits and read cache are shared with the copy.
+func (c *wavefrontClient) withContext(ctx context.Context) (*wavefrontClient, error) {
+	if c.client.Config == nil || c.client.Config.HTTPClient == nil {
+		return nil, fmt.Errorf("the Wavefront client has no http.Client")
+	}
+	config := *c.client.Config
+	httpClient := *config.HTTPClient
+	httpClient.Transport = &contextTransport{next: httpClient.Transport, ctx: ctx}
+	config.HTTPClient = &httpClient
+
+	client, err := wavefront.NewClient(&config)
+	if err != nil {
+		return nil, err
+	}
+	client.BaseURL = c.client.BaseURL
+
+	op := *c
+	op.client = *client
+	return &op, nil
+}
+
+// contextTransport sends every request with the context of a Terraform operation
+type contextTransport struct {
+	next http.RoundTripper
+	ctx  context.Context
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
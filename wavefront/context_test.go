@@ -0,0 +1,142 @@
+package wavefront_plugin
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestWithContext(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hang until the request is cancelled, which is only seen once the body sent by go-wavefront is read
+		ioutil.ReadAll(r.Body)
+		<-r.Context().Done()
+	}))
+	defer s.Close()
+
+	client, err := wavefront.NewClient(&wavefront.Config{
+		Address:    strings.TrimPrefix(s.URL, "http://"),
+		Token:      "token",
+		HTTPClient: &http.Client{Transport: http.DefaultTransport},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL.Scheme = "http"
+	c := &wavefrontClient{client: *client}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	op, err := c.withContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := "1"
+	start := time.Now()
+	err = op.client.Alerts().Get(&wavefront.Alert{ID: &id})
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("expected the request to be cancelled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the request to be cancelled after 100ms, took %s", elapsed)
+	}
+
+	// The provider's client is left as it was
+	if _, ok := c.client.Config.HTTPClient.Transport.(*contextTransport); ok {
+		t.Errorf("expected only the copy of the client to send requests with the context")
+	}
+}
+
+func TestAccWavefrontAlert_Timeout(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("a hung API is simulated by the mock Wavefront API")
+	}
+	var record wavefront.Alert
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { testAccMock.setStall(time.Minute) },
+				Config:      testAccCheckWavefrontAlert_timeout(),
+				ExpectError: regexp.MustCompile("error creating Wavefront Alert Terraform Test Alert Timeout.*context deadline exceeded"),
+			},
+			{
+				PreConfig: func() { testAccMock.setStall(0) },
+				Config:    testAccCheckWavefrontAlert_timeout(),
+				Check:     testAccCheckWavefrontAlertExists("wavefront_alert.test_alert", &record),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontAlert_timeout() string {
+	return fmt.Sprintf(`
+resource "wavefront_alert" "test_alert" {
+  name = "Terraform Test Alert Timeout"
+  target = "test@example.com"
+  condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
+  minutes = 5
+  severity = "WARN"
+  tags = ["terraform"]
+
+  timeouts {
+    create = "1s"
+  }
+}
+`)
+}
+
+func TestAccWavefrontEvent_Timeout(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("a hung API is simulated by the mock Wavefront API")
+	}
+	var record wavefront.Event
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontEventClosed,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   func() { testAccMock.setStall(time.Minute) },
+				Config:      testAccCheckWavefrontEvent_timeout(),
+				ExpectError: regexp.MustCompile("error creating Wavefront Event Terraform Test Event Timeout.*context deadline exceeded"),
+			},
+			{
+				PreConfig: func() { testAccMock.setStall(0) },
+				Config:    testAccCheckWavefrontEvent_timeout(),
+				Check:     testAccCheckWavefrontEventExists("wavefront_event.test_event", &record),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontEvent_timeout() string {
+	return fmt.Sprintf(`
+resource "wavefront_event" "test_event" {
+  name = "Terraform Test Event Timeout"
+  severity = "INFO"
+  tags = ["terraform"]
+
+  timeouts {
+    create = "1s"
+    read = "1m"
+    update = "1m"
+    delete = "1m"
+  }
+}
+`)
+}
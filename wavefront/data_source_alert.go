@@ -0,0 +1,214 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// The attributes of an Alert exposed by the alert data sources
+func dataSourceAlertAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"alert_type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"target": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"condition": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"threshold": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"severity": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"condition": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"targets": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"severity_list": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"additional_information": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"display_expression": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"minutes": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"resolve_after_minutes": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"notification_resend_frequency_minutes": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"severity": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"tags": {
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func dataSourceAlert() *schema.Resource {
+	s := dataSourceAlertAttributes()
+	s["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+	s["name"].Optional = true
+	s["tags"].Optional = true
+	s["cluster"] = clusterArgumentSchema(false)
+
+	return &schema.Resource{
+		Read:   dataSourceAlertRead,
+		Schema: s,
+	}
+}
+
+func dataSourceAlerts() *schema.Resource {
+	alert := dataSourceAlertAttributes()
+	alert["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return &schema.Resource{
+		Read: dataSourceAlertsRead,
+		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(false),
+			"name_contains": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"alerts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Resource{Schema: alert},
+			},
+		},
+	}
+}
+
+// Construct a Terraform Alert
+func buildTerraformAlert(a *wavefront.Alert) map[string]interface{} {
+	alert := map[string]interface{}{}
+	alert["name"] = a.Name
+	alert["alert_type"] = a.AlertType
+	alert["target"] = a.Target
+	alert["condition"] = trimSpaces(a.Condition)
+	alert["threshold"] = buildTerraformThresholds(a.Conditions, a.Targets)
+	alert["severity_list"] = thresholdSeverityList(a.Conditions)
+	alert["additional_information"] = trimSpaces(a.AdditionalInfo)
+	alert["display_expression"] = trimSpaces(a.DisplayExpression)
+	alert["minutes"] = a.Minutes
+	alert["resolve_after_minutes"] = a.ResolveAfterMinutes
+	alert["notification_resend_frequency_minutes"] = a.NotificationResendFrequencyMinutes
+	alert["severity"] = a.Severity
+	alert["tags"] = a.Tags
+	return alert
+}
+
+func dataSourceAlertRead(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	conditions := buildSearchConditions(d, "name")
+	if err := validateSearchConditions("alert", conditions); err != nil {
+		return err
+	}
+	results, err := c.findAlerts(conditions)
+	if err != nil {
+		return wavefrontError("searching for", "Alerts matching", describeSearchConditions(conditions), err)
+	}
+	err = validateSingleResult("alert", conditions, len(results))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(*results[0].ID)
+	for k, v := range buildTerraformAlert(results[0]) {
+		d.Set(k, v)
+	}
+
+	return nil
+}
+
+func dataSourceAlertsRead(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	conditions := buildSearchConditions(d, "name")
+	results, err := c.findAlerts(conditions)
+	if err != nil {
+		return wavefrontError("searching for", "Alerts matching", describeSearchConditions(conditions), err)
+	}
+
+	ids := []string{}
+	terraformAlerts := []map[string]interface{}{}
+	for _, a := range results {
+		alert := buildTerraformAlert(a)
+		alert["id"] = *a.ID
+		ids = append(ids, *a.ID)
+		terraformAlerts = append(terraformAlerts, alert)
+	}
+
+	d.SetId(dataSourceIdFromIds(ids))
+	d.Set("ids", ids)
+	if err := d.Set("alerts", terraformAlerts); err != nil {
+		return fmt.Errorf("failed to set alerts. %s", err)
+	}
+
+	return nil
+}
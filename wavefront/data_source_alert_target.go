@@ -0,0 +1,188 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// The attributes of a Target exposed by the alert target data sources
+func dataSourceTargetAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"triggers": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"template": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"method": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"recipient": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"email_subject": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"is_html_content": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"content_type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"custom_headers": {
+			Type:     schema.TypeMap,
+			Computed: true,
+		},
+	}
+}
+
+func dataSourceTarget() *schema.Resource {
+	s := dataSourceTargetAttributes()
+	s["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+	s["name"].Optional = true
+	s["cluster"] = clusterArgumentSchema(false)
+
+	return &schema.Resource{
+		Read:   dataSourceTargetRead,
+		Schema: s,
+	}
+}
+
+func dataSourceTargets() *schema.Resource {
+	target := dataSourceTargetAttributes()
+	target["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return &schema.Resource{
+		Read: dataSourceTargetsRead,
+		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(false),
+			"name_contains": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// 'method' must be EMAIL, WEBHOOK or PAGERDUTY
+			"method": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"targets": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Resource{Schema: target},
+			},
+		},
+	}
+}
+
+// Construct a Terraform Target
+func buildTerraformTarget(t *wavefront.Target) map[string]interface{} {
+	target := map[string]interface{}{}
+	target["name"] = t.Title
+	target["description"] = t.Description
+	target["triggers"] = t.Triggers
+	target["template"] = t.Template
+	target["method"] = t.Method
+	target["recipient"] = t.Recipient
+	target["email_subject"] = t.EmailSubject
+	target["is_html_content"] = t.IsHtmlContent
+	target["content_type"] = t.ContentType
+	target["custom_headers"] = t.CustomHeaders
+	return target
+}
+
+func dataSourceTargetRead(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	conditions := buildSearchConditions(d, "title")
+	if err := validateSearchConditions("alert target", conditions); err != nil {
+		return err
+	}
+	results, err := c.findTargets(conditions)
+	if err != nil {
+		return wavefrontError("searching for", "Alert Targets matching", describeSearchConditions(conditions), err)
+	}
+	err = validateSingleResult("alert target", conditions, len(results))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(*results[0].ID)
+	for k, v := range buildTerraformTarget(results[0]) {
+		d.Set(k, v)
+	}
+
+	return nil
+}
+
+func dataSourceTargetsRead(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	conditions := buildSearchConditions(d, "title")
+	if method, ok := d.GetOk("method"); ok {
+		conditions = append(conditions, &wavefront.SearchCondition{
+			Key:            "method",
+			Value:          method.(string),
+			MatchingMethod: "EXACT",
+		})
+	}
+
+	results, err := c.findTargets(conditions)
+	if err != nil {
+		return wavefrontError("searching for", "Alert Targets matching", describeSearchConditions(conditions), err)
+	}
+
+	ids := []string{}
+	terraformTargets := []map[string]interface{}{}
+	for _, t := range results {
+		target := buildTerraformTarget(t)
+		target["id"] = *t.ID
+		ids = append(ids, *t.ID)
+		terraformTargets = append(terraformTargets, target)
+	}
+
+	d.SetId(dataSourceIdFromIds(ids))
+	d.Set("ids", ids)
+	if err := d.Set("targets", terraformTargets); err != nil {
+		return fmt.Errorf("failed to set alert targets. %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccWavefrontTargetDataSource_ByName(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontTargetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontTargetDataSource_byName(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.wavefront_alert_target.by_name", "id", "wavefront_alert_target.test_target", "id"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert_target.by_name", "description", "Test target"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert_target.by_name", "method", "EMAIL"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert_target.by_name", "recipient", "test@example.com"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert_target.by_name", "triggers.#", "2"),
+					resource.TestCheckResourceAttrPair(
+						"data.wavefront_alert_target.by_id", "name", "wavefront_alert_target.test_target", "name"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontTargetsDataSource_ByMethod(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontTargetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontTargetsDataSource_byMethod(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert_targets.email", "ids.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.wavefront_alert_targets.email", "targets.0.id", "wavefront_alert_target.test_target", "id"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert_targets.email", "targets.0.email_subject", "This is a test"),
+				),
+			},
+		},
+	})
+}
+
+const testAccWavefrontTargetDataSourceTarget = `
+resource "wavefront_alert_target" "test_target" {
+  name = "Terraform Test Target Data Source"
+  description = "Test target"
+  method = "EMAIL"
+  recipient = "test@example.com"
+  email_subject = "This is a test"
+  is_html_content = true
+  template = "{}"
+  triggers = [
+    "ALERT_OPENED",
+    "ALERT_RESOLVED"
+  ]
+}
+`
+
+func testAccCheckWavefrontTargetDataSource_byName() string {
+	return fmt.Sprintf(`
+%s
+data "wavefront_alert_target" "by_name" {
+  name = wavefront_alert_target.test_target.name
+}
+
+data "wavefront_alert_target" "by_id" {
+  id = wavefront_alert_target.test_target.id
+}
+`, testAccWavefrontTargetDataSourceTarget)
+}
+
+func testAccCheckWavefrontTargetsDataSource_byMethod() string {
+	return fmt.Sprintf(`
+%s
+data "wavefront_alert_targets" "email" {
+  name_contains = wavefront_alert_target.test_target.name
+  method = "EMAIL"
+}
+`, testAccWavefrontTargetDataSourceTarget)
+}
@@ -0,0 +1,264 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccWavefrontAlertDataSource_ByID(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontAlertDataSource_byID(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.wavefront_alert.by_id", "id", "wavefront_alert.test_alert", "id"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.by_id", "name", "Terraform Test Alert Data Source"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.by_id", "alert_type", "CLASSIC"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.by_id", "target", "test@example.com"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.by_id", "severity", "WARN"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.by_id", "minutes", "5"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.by_id", "tags.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontAlertDataSource_ByName(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontAlertDataSource_byName(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.wavefront_alert.by_name", "id", "wavefront_alert.test_alert", "id"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.by_name", "condition", "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontAlertDataSource_Threshold(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontAlertDataSource_threshold(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.threshold", "alert_type", "THRESHOLD"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.threshold", "threshold.#", "2"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.threshold", "threshold.0.severity", "severe"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.threshold", "threshold.0.targets.0", "test@example.com"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.threshold", "threshold.1.severity", "warn"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.threshold", "severity_list.#", "2"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alert.threshold", "severity_list.0", "SEVERE"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontAlertDataSource_NoConditions(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("the searches made are counted by the mock Wavefront API")
+	}
+	searches := testAccMock.countRequests("POST", "/api/v2/search/alert")
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      `data "wavefront_alert" "any" {}`,
+				ExpectError: regexp.MustCompile(`at least one of id, name or tags must be set for the alert data source`),
+			},
+		},
+	})
+
+	// The conditions are checked before searching, rather than after searching every alert
+	if n := testAccMock.countRequests("POST", "/api/v2/search/alert") - searches; n != 0 {
+		t.Errorf("expected no searches without conditions, got %d", n)
+	}
+}
+
+func TestAccWavefrontAlertsDataSource_ByTag(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontAlertsDataSource_byTag(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alerts.by_tag", "ids.#", "2"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alerts.by_tag", "alerts.#", "2"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alerts.by_name", "ids.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"data.wavefront_alerts.by_name", "alerts.0.id", "wavefront_alert.test_alert_b", "id"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alerts.by_name", "alerts.0.name", "Terraform Test Alerts Data Source B"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontAlertsDataSource_Paged(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				// Every page of matching alerts is returned
+				Config: testAccCheckWavefrontProvider_searchPageSize(1) + testAccCheckWavefrontAlertsDataSource_byTag(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alerts.by_tag", "ids.#", "2"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_alerts.by_tag", "alerts.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontProvider_searchPageSize(size int) string {
+	return fmt.Sprintf(`
+provider "wavefront" {
+  search_page_size = %d
+}
+`, size)
+}
+
+const testAccWavefrontAlertDataSourceAlert = `
+resource "wavefront_alert" "test_alert" {
+  name = "Terraform Test Alert Data Source"
+  target = "test@example.com"
+  condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
+  additional_information = "This is a Terraform Test Alert"
+  minutes = 5
+  severity = "WARN"
+  tags = [
+    "terraform",
+    "datasource"
+  ]
+}
+`
+
+func testAccCheckWavefrontAlertDataSource_byID() string {
+	return fmt.Sprintf(`
+%s
+data "wavefront_alert" "by_id" {
+  id = wavefront_alert.test_alert.id
+}
+`, testAccWavefrontAlertDataSourceAlert)
+}
+
+func testAccCheckWavefrontAlertDataSource_byName() string {
+	return fmt.Sprintf(`
+%s
+data "wavefront_alert" "by_name" {
+  name = wavefront_alert.test_alert.name
+  tags = wavefront_alert.test_alert.tags
+}
+`, testAccWavefrontAlertDataSourceAlert)
+}
+
+func testAccCheckWavefrontAlertDataSource_threshold() string {
+	return fmt.Sprintf(`
+resource "wavefront_alert" "test_threshold_alert" {
+  name = "Terraform Test Alert Data Source Threshold"
+  alert_type = "THRESHOLD"
+  minutes = 5
+
+  threshold {
+    severity = "warn"
+    condition = "ts(\"cpu.usage_idle\") < 20"
+  }
+
+  threshold {
+    severity = "severe"
+    condition = "ts(\"cpu.usage_idle\") < 10"
+    targets = [
+      "test@example.com"
+    ]
+  }
+
+  tags = [
+    "terraform"
+  ]
+}
+
+data "wavefront_alert" "threshold" {
+  id = wavefront_alert.test_threshold_alert.id
+}
+`)
+}
+
+func testAccCheckWavefrontAlertsDataSource_byTag() string {
+	return fmt.Sprintf(`
+resource "wavefront_alert" "test_alert_a" {
+  name = "Terraform Test Alerts Data Source A"
+  target = "test@example.com"
+  condition = "ts(\"cpu.usage_idle\") < 10"
+  minutes = 5
+  severity = "WARN"
+  tags = [
+    "terraform",
+    "tfdatasources"
+  ]
+}
+
+resource "wavefront_alert" "test_alert_b" {
+  name = "Terraform Test Alerts Data Source B"
+  target = "test@example.com"
+  condition = "ts(\"cpu.usage_idle\") < 5"
+  minutes = 5
+  severity = "SEVERE"
+  tags = [
+    "terraform",
+    "tfdatasources"
+  ]
+}
+
+data "wavefront_alerts" "by_tag" {
+  tags = setintersection(wavefront_alert.test_alert_a.tags, wavefront_alert.test_alert_b.tags)
+}
+
+data "wavefront_alerts" "by_name" {
+  name_contains = replace(wavefront_alert.test_alert_b.name, "Terraform Test ", "")
+  tags = wavefront_alert.test_alert_b.tags
+}
+`)
+}
@@ -0,0 +1,230 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// The attributes of a Dashboard exposed by the dashboard data sources
+func dataSourceDashboardAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"url": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"display_section_table_of_contents": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"display_query_parameters": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"event_filter_type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"event_query": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"display_description": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"hidden": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"chart_title_bg_color": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"chart_title_color": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"chart_title_scalar": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"default_time_window": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"default_start_time": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"default_end_time": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"tags": {
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		// The full dashboard, in the same form as wavefront_dashboard_json
+		"dashboard_json": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+func dataSourceDashboard() *schema.Resource {
+	s := dataSourceDashboardAttributes()
+	s["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+	s["name"].Optional = true
+	s["tags"].Optional = true
+	s["cluster"] = clusterArgumentSchema(false)
+
+	return &schema.Resource{
+		Read:   dataSourceDashboardRead,
+		Schema: s,
+	}
+}
+
+func dataSourceDashboards() *schema.Resource {
+	dashboard := dataSourceDashboardAttributes()
+	dashboard["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return &schema.Resource{
+		Read: dataSourceDashboardsRead,
+		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(false),
+			"name_contains": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"dashboards": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Resource{Schema: dashboard},
+			},
+		},
+	}
+}
+
+// Construct a Terraform Dashboard summary
+func buildTerraformDashboard(dash *wavefront.Dashboard) (map[string]interface{}, error) {
+	bytes, err := dash.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := map[string]interface{}{}
+	dashboard["name"] = dash.Name
+	dashboard["description"] = dash.Description
+	dashboard["url"] = dash.Url
+	dashboard["display_section_table_of_contents"] = dash.DisplaySectionTableOfContents
+	dashboard["display_query_parameters"] = dash.DisplayQueryParameters
+	dashboard["event_filter_type"] = dash.EventFilterType
+	dashboard["event_query"] = dash.EventQuery
+	dashboard["display_description"] = dash.DisplayDescription
+	dashboard["hidden"] = dash.Hidden
+	dashboard["chart_title_bg_color"] = dash.ChartTitleBgColor
+	dashboard["chart_title_color"] = dash.ChartTitleColor
+	dashboard["chart_title_scalar"] = dash.ChartTitleScalar
+	dashboard["default_time_window"] = dash.DefaultTimeWindow
+	dashboard["default_start_time"] = dash.DefaultStartTime
+	dashboard["default_end_time"] = dash.DefaultEndTime
+	dashboard["tags"] = dash.Tags
+	dashboard["dashboard_json"] = NormalizeDashboardJson(string(bytes))
+	return dashboard, nil
+}
+
+func dataSourceDashboardRead(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	conditions := buildSearchConditions(d, "name")
+	if err := validateSearchConditions("dashboard", conditions); err != nil {
+		return err
+	}
+	results, err := c.findDashboards(conditions)
+	if err != nil {
+		return wavefrontError("searching for", "Dashboards matching", describeSearchConditions(conditions), err)
+	}
+	err = validateSingleResult("dashboard", conditions, len(results))
+	if err != nil {
+		return err
+	}
+
+	dashboard, err := buildTerraformDashboard(results[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse dashboard %s. %s", results[0].ID, err)
+	}
+
+	d.SetId(results[0].ID)
+	for k, v := range dashboard {
+		d.Set(k, v)
+	}
+
+	return nil
+}
+
+func dataSourceDashboardsRead(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	conditions := buildSearchConditions(d, "name")
+	results, err := c.findDashboards(conditions)
+	if err != nil {
+		return wavefrontError("searching for", "Dashboards matching", describeSearchConditions(conditions), err)
+	}
+
+	ids := []string{}
+	terraformDashboards := []map[string]interface{}{}
+	for _, dash := range results {
+		dashboard, err := buildTerraformDashboard(dash)
+		if err != nil {
+			return fmt.Errorf("failed to parse dashboard %s. %s", dash.ID, err)
+		}
+		dashboard["id"] = dash.ID
+		ids = append(ids, dash.ID)
+		terraformDashboards = append(terraformDashboards, dashboard)
+	}
+
+	d.SetId(dataSourceIdFromIds(ids))
+	d.Set("ids", ids)
+	if err := d.Set("dashboards", terraformDashboards); err != nil {
+		return fmt.Errorf("failed to set dashboards. %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccWavefrontDashboardDataSource_ByID(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontDashboardDataSource_byID(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboard.by_id", "id", "tftestdatasource"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboard.by_id", "name", "Terraform Test Dashboard Data Source"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboard.by_id", "url", "tftestdatasource"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboard.by_id", "tags.#", "2"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboard.by_id", "event_filter_type", "BYCHART"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboard.by_id", "hidden", "false"),
+					resource.TestCheckResourceAttrSet(
+						"data.wavefront_dashboard.by_id", "dashboard_json"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontDashboardsDataSource_ByTag(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontDashboardsDataSource_byTag(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboards.by_tag", "ids.#", "1"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboards.by_tag", "dashboards.0.id", "tftestdatasource"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_dashboards.by_tag", "dashboards.0.description", "testing, testing"),
+				),
+			},
+		},
+	})
+}
+
+const testAccWavefrontDashboardDataSourceDashboard = `
+resource "wavefront_dashboard" "test_dashboard" {
+  name = "Terraform Test Dashboard Data Source"
+  description = "testing, testing"
+  url = "tftestdatasource"
+  section {
+    name = "section 1"
+    row {
+      chart {
+        name = "chart 1"
+        description = "chart number 1"
+        units = "something per unit"
+        source {
+          name = "source name"
+          query = "ts()"
+        }
+        chart_setting {
+          type = "linear"
+        }
+        summarization = "MEAN"
+      }
+    }
+  }
+  tags = [
+    "terraform",
+    "tfdashboarddatasource"
+  ]
+}
+`
+
+func testAccCheckWavefrontDashboardDataSource_byID() string {
+	return fmt.Sprintf(`
+%s
+data "wavefront_dashboard" "by_id" {
+  id = wavefront_dashboard.test_dashboard.id
+}
+`, testAccWavefrontDashboardDataSourceDashboard)
+}
+
+func testAccCheckWavefrontDashboardsDataSource_byTag() string {
+	return fmt.Sprintf(`
+%s
+data "wavefront_dashboards" "by_tag" {
+  tags = wavefront_dashboard.test_dashboard.tags
+}
+`, testAccWavefrontDashboardDataSourceDashboard)
+}
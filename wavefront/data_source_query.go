@@ -0,0 +1,187 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func dataSourceQuery() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceQueryRead,
+
+		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(false),
+			"query": {
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    trimSpaces,
+				ValidateFunc: validateQuery,
+			},
+			// Length of the query window in seconds, ending at end_time
+			"time_window": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  wavefront.LastHour,
+			},
+			// Epoch seconds. Defaults to the time the data source is read
+			"end_time": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"start_time": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			// Granularity of the points returned, one of d, h, m or s
+			"granularity": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "m",
+				ValidateFunc: validateStringIn("d", "h", "m", "s"),
+			},
+			"summarization": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "MEAN",
+				ValidateFunc: validateStringIn("MEAN", "MEDIAN", "MIN", "MAX", "SUM", "COUNT", "LAST", "FIRST"),
+			},
+			"max_points": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"timeseries": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeMap,
+							Computed: true,
+						},
+						"data": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									// Epoch seconds
+									"timestamp": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"value": {
+										Type:     schema.TypeFloat,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"hosts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"stats": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"warnings": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Construct the Wavefront QueryParams for a query data source
+func buildQueryParams(d *schema.ResourceData, now time.Time) *wavefront.QueryParams {
+	endTime := now.Unix()
+	if e, ok := d.GetOk("end_time"); ok {
+		endTime = int64(e.(int))
+	}
+	startTime := endTime - int64(d.Get("time_window").(int))
+
+	params := &wavefront.QueryParams{
+		QueryString:           trimSpaces(d.Get("query").(string)),
+		StartTime:             strconv.FormatInt(startTime, 10),
+		EndTime:               strconv.FormatInt(endTime, 10),
+		Granularity:           d.Get("granularity").(string),
+		SummarizationStrategy: d.Get("summarization").(string),
+	}
+	if p, ok := d.GetOk("max_points"); ok {
+		params.MaxPoints = strconv.Itoa(p.(int))
+	}
+	return params
+}
+
+// Construct a Terraform TimeSeries
+func buildTerraformTimeSeries(ts wavefront.TimeSeries) map[string]interface{} {
+	timeSeries := map[string]interface{}{}
+	timeSeries["label"] = ts.Label
+	timeSeries["host"] = ts.Host
+	timeSeries["tags"] = ts.Tags
+
+	data := []map[string]interface{}{}
+	for _, point := range ts.DataPoints {
+		if len(point) != 2 {
+			continue
+		}
+		data = append(data, map[string]interface{}{
+			"timestamp": int(point[0]),
+			"value":     point[1],
+		})
+	}
+	timeSeries["data"] = data
+	return timeSeries
+}
+
+func dataSourceQueryRead(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	client := c.client
+
+	params := buildQueryParams(d, time.Now())
+	resp, err := client.NewQuery(params).Execute()
+	if err != nil {
+		return wavefrontError("executing", "query", params.QueryString, err)
+	}
+
+	timeSeries := []map[string]interface{}{}
+	for _, ts := range resp.TimeSeries {
+		timeSeries = append(timeSeries, buildTerraformTimeSeries(ts))
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(fmt.Sprintf("%s|%s|%s", params.QueryString, params.StartTime, params.EndTime))))
+	startTime, _ := strconv.Atoi(params.StartTime)
+	endTime, _ := strconv.Atoi(params.EndTime)
+	d.Set("start_time", startTime)
+	d.Set("end_time", endTime)
+	if err := d.Set("timeseries", timeSeries); err != nil {
+		return fmt.Errorf("failed to set timeseries for query %s. %s", params.QueryString, err)
+	}
+	d.Set("hosts", resp.Hosts)
+	d.Set("stats", resp.Stats)
+	d.Set("warnings", resp.Warnings)
+
+	return nil
+}
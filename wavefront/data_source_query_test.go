@@ -0,0 +1,123 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestAccWavefrontQueryDataSource_Basic(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontQueryDataSource_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.wavefront_query.constant", "start_time", "1569999400"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_query.constant", "end_time", "1570000000"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_query.constant", "timeseries.#", "1"),
+					resource.TestCheckResourceAttr(
+						"data.wavefront_query.constant", "timeseries.0.data.0.value", "42"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontQueryDataSource_basic() string {
+	return fmt.Sprintf(`
+data "wavefront_query" "constant" {
+  query = "42"
+  end_time = 1570000000
+  time_window = 600
+  granularity = "m"
+  summarization = "LAST"
+}
+`)
+}
+
+func TestDataSourceQuery_buildQueryParams(t *testing.T) {
+	now := time.Unix(1570000000, 0)
+
+	cases := []struct {
+		name     string
+		raw      map[string]interface{}
+		expected wavefront.QueryParams
+	}{
+		{
+			"defaults",
+			map[string]interface{}{
+				"query": " ts(cpu.load) ",
+			},
+			wavefront.QueryParams{
+				QueryString:           "ts(cpu.load)",
+				StartTime:             "1569996400",
+				EndTime:               "1570000000",
+				Granularity:           "m",
+				SummarizationStrategy: "MEAN",
+			},
+		},
+		{
+			"explicit window",
+			map[string]interface{}{
+				"query":         "ts(cpu.load)",
+				"end_time":      1500000000,
+				"time_window":   60,
+				"granularity":   "s",
+				"summarization": "MAX",
+				"max_points":    10,
+			},
+			wavefront.QueryParams{
+				QueryString:           "ts(cpu.load)",
+				StartTime:             "1499999940",
+				EndTime:               "1500000000",
+				Granularity:           "s",
+				SummarizationStrategy: "MAX",
+				MaxPoints:             "10",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, dataSourceQuery().Schema, c.raw)
+		params := buildQueryParams(d, now)
+		if *params != c.expected {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, *params)
+		}
+	}
+}
+
+func TestDataSourceQuery_buildTerraformTimeSeries(t *testing.T) {
+	ts := buildTerraformTimeSeries(wavefront.TimeSeries{
+		Label: "cpu.load",
+		Host:  "host-1",
+		Tags:  map[string]string{"env": "test"},
+		DataPoints: []wavefront.DataPoint{
+			{1570000000, 1.5},
+			{1570000060},
+			{1570000120, 2},
+		},
+	})
+
+	if ts["label"] != "cpu.load" || ts["host"] != "host-1" {
+		t.Errorf("unexpected label or host %v", ts)
+	}
+	data := ts["data"].([]map[string]interface{})
+	if len(data) != 2 {
+		t.Fatalf("expected malformed data points to be skipped, got %v", data)
+	}
+	if data[0]["timestamp"] != 1570000000 || data[0]["value"] != 1.5 {
+		t.Errorf("unexpected first data point %v", data[0])
+	}
+	if data[1]["timestamp"] != 1570000120 || data[1]["value"] != 2.0 {
+		t.Errorf("unexpected second data point %v", data[1])
+	}
+}
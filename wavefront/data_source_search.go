@@ -0,0 +1,86 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// Construct the search conditions for a data source lookup. Conditions are combined as a logical AND.
+// nameKey is the search key holding the name of the object, "name" for most objects but "title" for alert targets.
+func buildSearchConditions(d *schema.ResourceData, nameKey string) []*wavefront.SearchCondition {
+	var conditions []*wavefront.SearchCondition
+
+	if id, ok := d.GetOk("id"); ok {
+		conditions = append(conditions, &wavefront.SearchCondition{
+			Key:            "id",
+			Value:          id.(string),
+			MatchingMethod: "EXACT",
+		})
+	}
+	if name, ok := d.GetOk("name"); ok {
+		conditions = append(conditions, &wavefront.SearchCondition{
+			Key:            nameKey,
+			Value:          name.(string),
+			MatchingMethod: "EXACT",
+		})
+	}
+	if name, ok := d.GetOk("name_contains"); ok {
+		conditions = append(conditions, &wavefront.SearchCondition{
+			Key:            nameKey,
+			Value:          name.(string),
+			MatchingMethod: "CONTAINS",
+		})
+	}
+	if tags, ok := d.GetOk("tags"); ok {
+		for _, tag := range tags.(*schema.Set).List() {
+			conditions = append(conditions, &wavefront.SearchCondition{
+				Key:            "tags",
+				Value:          tag.(string),
+				MatchingMethod: "EXACT",
+			})
+		}
+	}
+
+	return conditions
+}
+
+// A single object data source must be given at least one condition, checked before searching so that it doesn't
+// search every object of the kind
+func validateSearchConditions(kind string, conditions []*wavefront.SearchCondition) error {
+	if len(conditions) == 0 {
+		return fmt.Errorf("at least one of id, name or tags must be set for the %s data source", kind)
+	}
+	return nil
+}
+
+// A single object data source must find exactly one match
+func validateSingleResult(kind string, conditions []*wavefront.SearchCondition, count int) error {
+	if count == 0 {
+		return fmt.Errorf("no %s found matching %s", kind, describeSearchConditions(conditions))
+	}
+	if count > 1 {
+		return fmt.Errorf("%d %ss found matching %s, use a more specific search", count, kind, describeSearchConditions(conditions))
+	}
+	return nil
+}
+
+func describeSearchConditions(conditions []*wavefront.SearchCondition) string {
+	var s []string
+	for _, c := range conditions {
+		s = append(s, fmt.Sprintf("%s %s %q", c.Key, c.MatchingMethod, c.Value))
+	}
+	return strings.Join(s, " and ")
+}
+
+// The ID of a multiple object data source is derived from the IDs it found
+func dataSourceIdFromIds(ids []string) string {
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+	return strconv.Itoa(hashcode.String(strings.Join(sorted, ",")))
+}
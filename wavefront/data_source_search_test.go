@@ -0,0 +1,87 @@
+package wavefront_plugin
+
+import (
+	"testing"
+)
+
+func TestBuildSearchConditions(t *testing.T) {
+	d := dataSourceAlert().TestResourceData()
+	d.Set("id", "1234")
+	d.Set("name", "test")
+	d.Set("tags", []string{"a", "b"})
+
+	conditions := buildSearchConditions(d, "name")
+	if len(conditions) != 4 {
+		t.Fatalf("expected 4 conditions, got %d", len(conditions))
+	}
+	if conditions[0].Key != "id" || conditions[0].Value != "1234" || conditions[0].MatchingMethod != "EXACT" {
+		t.Errorf("unexpected id condition %v", conditions[0])
+	}
+	if conditions[1].Key != "name" || conditions[1].Value != "test" {
+		t.Errorf("unexpected name condition %v", conditions[1])
+	}
+	for _, c := range conditions[2:] {
+		if c.Key != "tags" {
+			t.Errorf("expected tags condition, got %v", c)
+		}
+	}
+
+	targets := dataSourceTargets().TestResourceData()
+	targets.Set("name_contains", "slack")
+
+	conditions = buildSearchConditions(targets, "title")
+	if len(conditions) != 1 {
+		t.Fatalf("expected 1 condition, got %d", len(conditions))
+	}
+	if conditions[0].Key != "title" || conditions[0].MatchingMethod != "CONTAINS" {
+		t.Errorf("unexpected name_contains condition %v", conditions[0])
+	}
+}
+
+func TestValidateSearchConditions(t *testing.T) {
+	d := dataSourceAlert().TestResourceData()
+	err := validateSearchConditions("alert", buildSearchConditions(d, "name"))
+	if err == nil || err.Error() != "at least one of id, name or tags must be set for the alert data source" {
+		t.Errorf("expected an error when no search conditions are set, got %v", err)
+	}
+
+	d.Set("tags", []string{"a"})
+	if err := validateSearchConditions("alert", buildSearchConditions(d, "name")); err != nil {
+		t.Errorf("expected a tag to be enough to search, got %v", err)
+	}
+}
+
+func TestValidateSingleResult(t *testing.T) {
+	d := dataSourceAlert().TestResourceData()
+	d.Set("name", "test")
+	conditions := buildSearchConditions(d, "name")
+
+	cases := []struct {
+		count        int
+		errorMessage string
+	}{
+		{0, `no alert found matching name EXACT "test"`},
+		{1, ""},
+		{2, `2 alerts found matching name EXACT "test", use a more specific search`},
+	}
+
+	for _, c := range cases {
+		err := validateSingleResult("alert", conditions, c.count)
+		m := ""
+		if err != nil {
+			m = err.Error()
+		}
+		if m != c.errorMessage {
+			t.Errorf("expected error '%s', got '%s'", c.errorMessage, m)
+		}
+	}
+}
+
+func TestDataSourceIdFromIds(t *testing.T) {
+	if dataSourceIdFromIds([]string{"a", "b"}) != dataSourceIdFromIds([]string{"b", "a"}) {
+		t.Errorf("expected the id to be independent of the order of the ids")
+	}
+	if dataSourceIdFromIds([]string{"a"}) == dataSourceIdFromIds([]string{"a", "b"}) {
+		t.Errorf("expected different ids for different results")
+	}
+}
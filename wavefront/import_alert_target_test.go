@@ -13,7 +13,7 @@ func TestAccTarget_importBasic(t *testing.T) {
 	resourceName := "wavefront_alert_target.foobar"
 	var record wavefront.Target
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontTargetDestroy,
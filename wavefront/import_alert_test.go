@@ -12,7 +12,7 @@ func TestAccAlert_importBasic(t *testing.T) {
 	resourceName := "wavefront_alert.foobar"
 	var record wavefront.Alert
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontAlertDestroy,
@@ -36,7 +36,7 @@ func TestAccAlert_importThreshold(t *testing.T) {
 	resourceName := "wavefront_alert.test_threshold_alert"
 	var record wavefront.Alert
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontAlertDestroy,
@@ -99,16 +99,24 @@ resource "wavefront_alert" "test_threshold_alert" {
   minutes = 5
   resolve_after_minutes = 5
 
-  threshold_conditions = {
-    "severe" = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
-    "warn" = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 60"
-    "info" = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 50"
+  threshold {
+    severity = "severe"
+    condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
+    targets = [
+      "target:${wavefront_alert_target.test_target.id}"
+    ]
   }
 
-  threshold_targets = {
-	"severe" = "target:${wavefront_alert_target.test_target.id}"
+  threshold {
+    severity = "warn"
+    condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 60"
   }
-  
+
+  threshold {
+    severity = "info"
+    condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 50"
+  }
+
   tags = [
     "terraform"
   ]
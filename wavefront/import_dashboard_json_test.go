@@ -11,7 +11,7 @@ func TestAccDashboardJson_importBasic(t *testing.T) {
 	resourceName := "wavefront_dashboard_json.json_foobar"
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardJsonDestroy,
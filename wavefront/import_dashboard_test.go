@@ -13,7 +13,7 @@ func TestAccDashboard_importBasic(t *testing.T) {
 	resourceName := "wavefront_dashboard.foobar"
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -0,0 +1,48 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestAccEvent_importBasic(t *testing.T) {
+	resourceName := "wavefront_event.foobar"
+	var record wavefront.Event
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontEventClosed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontEventImporter_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontEventExists("wavefront_event.foobar", &record),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"delete_on_destroy"},
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontEventImporter_basic() string {
+	return fmt.Sprintf(`
+resource "wavefront_event" "foobar" {
+  name = "Terraform Test Event"
+  severity = "SEVERE"
+  type = "Maintenance"
+  details = "Terraform import test"
+  tags = [
+    "terraform"
+  ]
+}
+`)
+}
@@ -0,0 +1,568 @@
+package wavefront_plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+const (
+	mockWavefrontToken     = "mock-wavefront-token"
+	mockWavefrontRequestID = "mock-request-id"
+)
+
+// mockWavefront is an in-memory stand-in for the parts of the Wavefront API used by the provider.
+// It serves /api/v2/alert, /api/v2/notificant, /api/v2/dashboard, /api/v2/event, /api/v2/search/{type}
+// and /api/v2/chart/api so that the acceptance tests can be run without a Wavefront tenant. It also serves an
+// OAuth 2 token endpoint at /oauth/token, exchanging mockWavefrontToken for access tokens.
+type mockWavefront struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	nextID  int
+	objects map[string]map[string]map[string]interface{}
+	// The number of requests still to be rejected with a 429 by throttle
+	throttled int
+	// The access tokens issued by the mock's token endpoint, and when they expire
+	accessTokens map[string]time.Time
+	exchanges    int
+	// The method and path of every request made to the API
+	requests []string
+	// How long each request to the API is held before it is served, unless it is cancelled first
+	stall time.Duration
+}
+
+// The collections served by the mock, keyed by the path segment (and search type) used by the API
+var mockWavefrontCollections = []string{"alert", "notificant", "dashboard", "event"}
+
+func newMockWavefront() *mockWavefront {
+	m := &mockWavefront{
+		nextID:       1000,
+		objects:      map[string]map[string]map[string]interface{}{},
+		accessTokens: map[string]time.Time{},
+	}
+	for _, c := range mockWavefrontCollections {
+		m.objects[c] = map[string]map[string]interface{}{}
+	}
+	m.server = httptest.NewTLSServer(http.HandlerFunc(m.serveHTTP))
+	return m
+}
+
+// Address returns the host:port of the mock, in the form expected by the provider's address argument
+func (m *mockWavefront) Address() string {
+	u, _ := url.Parse(m.server.URL)
+	return u.Host
+}
+
+func (m *mockWavefront) Close() {
+	m.server.Close()
+}
+
+// writeCertificate writes the mock's self-signed certificate to a PEM file, for use as the provider's ca_cert_file
+func (m *mockWavefront) writeCertificate() (string, error) {
+	f, err := ioutil.TempFile("", "mock-wavefront-*.pem")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	err = pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: m.server.Certificate().Raw})
+	return f.Name(), err
+}
+
+// exists reports whether the mock holds the object with the ID in a collection
+func (m *mockWavefront) exists(collection, id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.objects[collection][id]
+	return ok
+}
+
+// edit changes an object as if it were edited in the Wavefront UI
+func (m *mockWavefront) edit(collection, id string, change func(obj map[string]interface{})) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if obj, ok := m.objects[collection][id]; ok {
+		change(obj)
+	}
+}
+
+// countRequests returns the number of requests made to the API with the method and a path starting with prefix
+func (m *mockWavefront) countRequests(method, prefix string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, r := range m.requests {
+		if strings.HasPrefix(r, method+" "+prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+// setStall holds every request to the API for d before serving it, simulating a hung API
+func (m *mockWavefront) setStall(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stall = d
+}
+
+// throttle rejects the next n requests with a 429, as Wavefront does when a tenant's API rate limit is exceeded
+func (m *mockWavefront) throttle(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.throttled = n
+}
+
+// authorized reports whether a request has mockWavefrontToken, or an access token issued by the token endpoint.
+// Only the token endpoint accepts mockWavefrontToken in the body of the request.
+func (m *mockWavefront) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == mockWavefrontToken {
+		return true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expiry, ok := m.accessTokens[token]
+	return ok && time.Now().Before(expiry)
+}
+
+// exchangeToken issues an access token for the refresh_token grant of mockWavefrontToken
+func (m *mockWavefront) exchangeToken(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.PostFormValue("grant_type") != "refresh_token" || r.PostFormValue("refresh_token") != mockWavefrontToken {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	m.mu.Lock()
+	m.exchanges++
+	token := fmt.Sprintf("mock-access-token-%d", m.exchanges)
+	m.accessTokens[token] = time.Now().Add(time.Hour)
+	m.mu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": token,
+		"token_type":   "bearer",
+		"expires_in":   3600,
+	})
+}
+
+func (m *mockWavefront) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/oauth/token" && r.Method == http.MethodPost {
+		m.exchangeToken(w, r)
+		return
+	}
+	if !m.authorized(r) {
+		writeMockError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	m.mu.Lock()
+	m.requests = append(m.requests, r.Method+" "+r.URL.Path)
+	throttled := m.throttled > 0
+	if throttled {
+		m.throttled--
+	}
+	stall := m.stall
+	m.mu.Unlock()
+	if stall > 0 {
+		// A cancelled request is only seen once its body has been read
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		select {
+		case <-time.After(stall):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if throttled {
+		w.Header().Set("Retry-After", "0")
+		writeMockError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2"), "/"), "/")
+	if parts[0] == "search" && len(parts) == 2 && r.Method == http.MethodPost {
+		m.search(w, r, parts[1])
+		return
+	}
+
+	if parts[0] == "chart" && len(parts) == 2 && parts[1] == "api" && r.Method == http.MethodGet {
+		m.query(w, r)
+		return
+	}
+
+	if parts[0] == "event" && len(parts) == 3 && parts[2] == "close" && r.Method == http.MethodPost {
+		m.closeEvent(w, parts[1])
+		return
+	}
+
+	if !m.isCollection(parts[0]) || len(parts) > 2 {
+		writeMockError(w, http.StatusNotFound, fmt.Sprintf("no such path %s", r.URL.Path))
+		return
+	}
+
+	collection := parts[0]
+	if len(parts) == 1 {
+		if r.Method != http.MethodPost {
+			writeMockError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		m.create(w, r, collection)
+		return
+	}
+
+	id := parts[1]
+	switch r.Method {
+	case http.MethodGet:
+		m.get(w, collection, id)
+	case http.MethodPut:
+		m.update(w, r, collection, id)
+	case http.MethodDelete:
+		m.delete(w, collection, id)
+	default:
+		writeMockError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (m *mockWavefront) isCollection(name string) bool {
+	for _, c := range mockWavefrontCollections {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockWavefront) create(w http.ResponseWriter, r *http.Request, collection string) {
+	obj, err := decodeMockObject(r, collection)
+	if err != nil {
+		writeMockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Dashboards are identified by their url, everything else is assigned an ID by Wavefront
+	var id string
+	if collection == "dashboard" {
+		id, _ = obj["url"].(string)
+		if id == "" {
+			writeMockError(w, http.StatusBadRequest, "dashboard url must be set")
+			return
+		}
+		if _, ok := m.objects[collection][id]; ok {
+			writeMockError(w, http.StatusBadRequest, fmt.Sprintf("dashboard %s already exists", id))
+			return
+		}
+	} else {
+		m.nextID++
+		id = strconv.Itoa(m.nextID)
+	}
+
+	obj["id"] = id
+	obj["createdEpochMillis"] = 1500000000000
+	obj["updatedEpochMillis"] = 1500000000000
+	obj["creatorId"] = "mock@example.com"
+	obj["updaterId"] = "mock@example.com"
+	m.objects[collection][id] = obj
+
+	writeMockResponse(w, obj)
+}
+
+func (m *mockWavefront) get(w http.ResponseWriter, collection, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[collection][id]
+	if !ok {
+		writeMockError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", collection, id))
+		return
+	}
+	writeMockResponse(w, obj)
+}
+
+func (m *mockWavefront) update(w http.ResponseWriter, r *http.Request, collection, id string) {
+	obj, err := decodeMockObject(r, collection)
+	if err != nil {
+		writeMockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.objects[collection][id]
+	if !ok {
+		writeMockError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", collection, id))
+		return
+	}
+
+	obj["id"] = id
+	obj["createdEpochMillis"] = existing["createdEpochMillis"]
+	obj["creatorId"] = existing["creatorId"]
+	obj["updatedEpochMillis"] = 1500000000001
+	obj["updaterId"] = "mock@example.com"
+	m.objects[collection][id] = obj
+
+	writeMockResponse(w, obj)
+}
+
+func (m *mockWavefront) delete(w http.ResponseWriter, collection, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[collection][id]
+	if !ok {
+		writeMockError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", collection, id))
+		return
+	}
+	delete(m.objects[collection], id)
+	writeMockResponse(w, obj)
+}
+
+func (m *mockWavefront) closeEvent(w http.ResponseWriter, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects["event"][id]
+	if !ok {
+		writeMockError(w, http.StatusNotFound, fmt.Sprintf("event %s not found", id))
+		return
+	}
+	obj["endTime"] = time.Now().Unix() * 1000
+	writeMockResponse(w, obj)
+}
+
+func (m *mockWavefront) search(w http.ResponseWriter, r *http.Request, collection string) {
+	if !m.isCollection(collection) {
+		writeMockError(w, http.StatusNotFound, fmt.Sprintf("unsupported search type %s", collection))
+		return
+	}
+
+	params := wavefront.SearchParams{}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeMockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if params.Limit == 0 {
+		params.Limit = 100
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(m.objects[collection]))
+	for id := range m.objects[collection] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	matched := []map[string]interface{}{}
+	for _, id := range ids {
+		obj := m.objects[collection][id]
+		if mockObjectMatches(obj, params.Conditions) {
+			matched = append(matched, obj)
+		}
+	}
+
+	items := []map[string]interface{}{}
+	if params.Offset < len(matched) {
+		end := params.Offset + params.Limit
+		if end > len(matched) {
+			end = len(matched)
+		}
+		items = matched[params.Offset:end]
+	}
+
+	writeMockResponse(w, map[string]interface{}{
+		"items":      items,
+		"offset":     params.Offset,
+		"limit":      params.Limit,
+		"totalItems": len(matched),
+		"moreItems":  params.Offset+params.Limit < len(matched),
+	})
+}
+
+// mockObjectMatches reports whether obj satisfies all of the search conditions
+func mockObjectMatches(obj map[string]interface{}, conditions []*wavefront.SearchCondition) bool {
+	for _, c := range conditions {
+		var values []string
+		switch v := obj[c.Key].(type) {
+		case string:
+			values = []string{v}
+		case bool, float64, int:
+			values = []string{fmt.Sprint(v)}
+		case map[string]interface{}:
+			// tags are serialised as {"customerTags": [...]}
+			if tags, ok := v["customerTags"].([]interface{}); ok {
+				for _, t := range tags {
+					values = append(values, fmt.Sprint(t))
+				}
+			}
+		case []interface{}:
+			for _, e := range v {
+				values = append(values, fmt.Sprint(e))
+			}
+		}
+
+		found := false
+		for _, value := range values {
+			if mockValueMatches(value, c.Value, c.MatchingMethod) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func mockValueMatches(value, search, method string) bool {
+	switch method {
+	case "CONTAINS":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(search))
+	case "STARTSWITH":
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(search))
+	case "TAGPATH":
+		return value == search || strings.HasPrefix(value, search+".")
+	default:
+		return value == search
+	}
+}
+
+func decodeMockObject(r *http.Request, collection string) (map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("invalid request body: %s", err)
+	}
+	// Like Wavefront, custom HTTP headers are accepted on targets but not returned, which the
+	// target tests expect
+	if collection == "notificant" {
+		delete(obj, "customHttpHeaders")
+	}
+	if collection == "dashboard" {
+		setMockDashboardDefaults(obj)
+	}
+	return obj, nil
+}
+
+// setMockDashboardDefaults fills in some of the defaults Wavefront gives the charts and rows of a dashboard which
+// omits them
+func setMockDashboardDefaults(dashboard map[string]interface{}) {
+	setDefault := func(obj map[string]interface{}, key string, value interface{}) {
+		if v, ok := obj[key]; !ok || v == nil || v == "" || v == float64(0) {
+			obj[key] = value
+		}
+	}
+	sections, _ := dashboard["sections"].([]interface{})
+	for _, section := range sections {
+		rows, _ := section.(map[string]interface{})["rows"].([]interface{})
+		for _, row := range rows {
+			row := row.(map[string]interface{})
+			setDefault(row, "heightFactor", float64(50))
+			charts, _ := row["charts"].([]interface{})
+			for _, chart := range charts {
+				chart := chart.(map[string]interface{})
+				setDefault(chart, "base", float64(1))
+				setDefault(chart, "summarization", "MEAN")
+				settings, ok := chart["chartSettings"].(map[string]interface{})
+				if !ok {
+					settings = map[string]interface{}{}
+					chart["chartSettings"] = settings
+				}
+				setDefault(settings, "type", "line")
+			}
+		}
+	}
+}
+
+// query answers the chart API. Only constant queries such as "42" are evaluated, as a single series with
+// one point per granularity step; any other query returns no series and a warning.
+func (m *mockWavefront) query(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	start, err := strconv.ParseInt(params.Get("s"), 10, 64)
+	if err != nil {
+		writeMockError(w, http.StatusBadRequest, "invalid start time")
+		return
+	}
+	end, err := strconv.ParseInt(params.Get("e"), 10, 64)
+	if err != nil {
+		end = time.Now().Unix()
+	}
+	step := map[string]int64{"s": 1, "m": 60, "h": 3600, "d": 86400}[params.Get("g")]
+	if step == 0 {
+		step = 60
+	}
+
+	response := map[string]interface{}{
+		"query":       params.Get("q"),
+		"name":        params.Get("q"),
+		"granularity": step,
+		"hostsUsed":   []string{},
+		"timeseries":  []interface{}{},
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(params.Get("q")), 64)
+	if err != nil {
+		response["warnings"] = fmt.Sprintf("No metrics matching %s", params.Get("q"))
+	} else {
+		data := [][]float64{}
+		for ts := start - start%step + step; ts <= end; ts += step {
+			data = append(data, []float64{float64(ts), value})
+		}
+		response["timeseries"] = []interface{}{
+			map[string]interface{}{
+				"label": params.Get("q"),
+				"host":  "mock-host",
+				"tags":  map[string]string{"source": "mock"},
+				"data":  data,
+			},
+		}
+		response["hostsUsed"] = []string{"mock-host"}
+		response["stats"] = map[string]int{"keys": 1, "points": len(data)}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func writeMockResponse(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": map[string]interface{}{
+			"result": "OK",
+			"code":   http.StatusOK,
+		},
+		"response": response,
+	})
+}
+
+func writeMockError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-ID", mockWavefrontRequestID)
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": map[string]interface{}{
+			"result":  "ERROR",
+			"message": message,
+			"code":    code,
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package wavefront_plugin
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -9,21 +10,152 @@ import (
 )
 
 type wavefrontClient struct {
-	client wavefront.Client
+	client      wavefront.Client
+	limiter     *requestLimiter
+	defaultTags []string
+	// Serves the reads of resources, nil when read_cache is disabled
+	cache *readCache
+	// The number of results fetched by each request of a search
+	searchPageSize int
+	// The provider's address, empty when only cluster blocks are configured
+	address string
+	// The clients of the provider's cluster blocks, keyed by name
+	clusters map[string]*wavefrontClient
+	// Returns the context cancelled when Terraform is interrupted
+	stopContext func() context.Context
 }
 
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"address": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("WAVEFRONT_ADDRESS", ""),
+				Description: "The address of the Wavefront cluster used when a resource sets no cluster",
 			},
+			"cluster": clusterSchema(),
 			"token": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("WAVEFRONT_TOKEN", ""),
+				Description: "The API token, one of token, token_file or token_command must be set",
+			},
+			"token_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WAVEFRONT_TOKEN_FILE", ""),
+				Description: "A file containing the API token, instead of token or token_command",
+			},
+			"token_command": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: "A program and its arguments which print the API token, instead of token or token_file. " +
+					"It is run once for the lifetime of the provider process, and killed after a minute.",
+			},
+			"token_exchange": tokenExchangeSchema(),
+			"default_tags": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Tags added to every alert, dashboard and dashboard_json",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": &schema.Schema{
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"http_proxy": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WAVEFRONT_HTTP_PROXY", ""),
+				Description: "The URL of the proxy requests are sent through, HTTPS_PROXY is used when not set",
+			},
+			"ca_cert_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WAVEFRONT_CA_CERT_FILE", ""),
+				Description: "A PEM file of CA certificates trusted in addition to the system's",
+			},
+			"client_cert_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WAVEFRONT_CLIENT_CERT_FILE", ""),
+				Description: "A PEM file of the client certificate presented to Wavefront or the proxy",
+			},
+			"client_key_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WAVEFRONT_CLIENT_KEY_FILE", ""),
+				Description: "A PEM file of the private key of client_cert_file",
+			},
+			"insecure_skip_verify": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("WAVEFRONT_INSECURE_SKIP_VERIFY", false),
+				Description: "Disables TLS certificate verification, for test tenants only",
+			},
+			"request_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("WAVEFRONT_REQUEST_TIMEOUT", 0),
+				Description:  "The number of seconds each request may take, 0 for no timeout",
+				ValidateFunc: validateIntAtLeast(0),
+			},
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				Description:  "The number of times a request throttled (429) or failed with a 502, 503 or 504 is retried",
+				ValidateFunc: validateIntAtLeast(0),
+			},
+			"retry_min_wait": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "The number of seconds to wait before the first retry, doubling with each retry",
+				ValidateFunc: validateIntAtLeast(0),
+			},
+			"retry_max_wait": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				Description:  "The maximum number of seconds to wait between retries, including any Retry-After sent by Wavefront",
+				ValidateFunc: validateIntAtLeast(0),
+			},
+			"max_concurrent_requests": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				Description:  "The maximum number of requests sent to each Wavefront cluster at once, 0 for no limit",
+				ValidateFunc: validateIntAtLeast(0),
+			},
+			"requests_per_second": &schema.Schema{
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0.0,
+				Description:  "The maximum number of requests sent to each Wavefront cluster per second, 0 for no limit",
+				ValidateFunc: validateFloatAtLeast(0),
+			},
+			"read_cache": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+				Description: "Prefetches every alert, alert target and dashboard with the search API once more than a few " +
+					"of them are read, rather than reading each with its own request",
+			},
+			"search_page_size": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      maxSearchPageSize,
+				Description:  "The number of results fetched by each request of a search, up to 1000",
+				ValidateFunc: validateIntBetween(1, maxSearchPageSize),
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
@@ -31,22 +163,65 @@ func Provider() terraform.ResourceProvider {
 			"wavefront_dashboard":      resourceDashboard(),
 			"wavefront_dashboard_json": resourceDashboardJson(),
 			"wavefront_alert_target":   resourceTarget(),
+			"wavefront_event":          resourceEvent(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"wavefront_alert":         dataSourceAlert(),
+			"wavefront_alerts":        dataSourceAlerts(),
+			"wavefront_alert_target":  dataSourceTarget(),
+			"wavefront_alert_targets": dataSourceTargets(),
+			"wavefront_dashboard":     dataSourceDashboard(),
+			"wavefront_dashboards":    dataSourceDashboards(),
+			"wavefront_query":         dataSourceQuery(),
 		},
-		ConfigureFunc: providerConfigure,
 	}
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(d, p.StopContext)
+	}
+	return p
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	config := &wavefront.Config{
-		Address: d.Get("address").(string),
-		Token:   d.Get("token").(string),
+func providerConfigure(d *schema.ResourceData, stopContext func() context.Context) (interface{}, error) {
+	if min, max := d.Get("retry_min_wait").(int), d.Get("retry_max_wait").(int); min > max {
+		return nil, fmt.Errorf("retry_min_wait (%d) must not be greater than retry_max_wait (%d)", min, max)
 	}
-	wFClient, err := wavefront.NewClient(config)
+
+	clusters, err := configureClusters(d)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to configure Wavefront Client %s", err)
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		cluster.stopContext = stopContext
 	}
-	return &wavefrontClient{
-		client: *wFClient,
-	}, nil
 
+	address := d.Get("address").(string)
+	if address == "" {
+		if len(clusters) == 0 {
+			return nil, fmt.Errorf("one of address or a cluster block must be set")
+		}
+		return &wavefrontClient{
+			defaultTags: setStrings(d.Get("default_tags.0.tags")),
+			clusters:    clusters,
+			stopContext: stopContext,
+		}, nil
+	}
+
+	token, err := resolveToken(d)
+	if err != nil {
+		return nil, err
+	}
+	config := &wavefront.Config{
+		Address:       address,
+		Token:         token,
+		HttpProxy:     d.Get("http_proxy").(string),
+		SkipTLSVerify: d.Get("insecure_skip_verify").(bool),
+	}
+	client, err := newWavefrontClient(d, config, d.Get("token_exchange").([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	client.address = address
+	client.clusters = clusters
+	client.stopContext = stopContext
+	return client, nil
 }
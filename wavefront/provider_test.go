@@ -3,6 +3,7 @@ package wavefront_plugin
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"os"
@@ -11,6 +12,9 @@ import (
 var testAccProviders map[string]terraform.ResourceProvider
 var testAccProvider *schema.Provider
 
+// testAccMock is the mock Wavefront API the acceptance tests run against when no tenant is configured
+var testAccMock *mockWavefront
+
 func init() {
 	testAccProvider = Provider().(*schema.Provider)
 	testAccProviders = map[string]terraform.ResourceProvider{
@@ -18,6 +22,37 @@ func init() {
 	}
 }
 
+// TestMain runs the acceptance tests against an in-memory mock of the Wavefront API unless
+// WAVEFRONT_ADDRESS and WAVEFRONT_TOKEN point at a real tenant.
+func TestMain(m *testing.M) {
+	if os.Getenv("WAVEFRONT_ADDRESS") == "" && os.Getenv("WAVEFRONT_TOKEN") == "" {
+		// The mock's certificate is self-signed, so it is trusted through the provider's ca_cert_file
+		testAccMock = newMockWavefront()
+		caFile, err := testAccMock.writeCertificate()
+		if err != nil {
+			panic(err)
+		}
+		os.Setenv("WAVEFRONT_ADDRESS", testAccMock.Address())
+		os.Setenv("WAVEFRONT_TOKEN", mockWavefrontToken)
+		os.Setenv("WAVEFRONT_CA_CERT_FILE", caFile)
+	}
+
+	code := m.Run()
+
+	if testAccMock != nil {
+		testAccMock.Close()
+		os.Remove(os.Getenv("WAVEFRONT_CA_CERT_FILE"))
+	}
+	os.Exit(code)
+}
+
+// testAccResourceTest runs an acceptance test case. Against the mock Wavefront API the case runs as
+// part of the normal unit test suite, otherwise TF_ACC must be set as usual.
+func testAccResourceTest(t *testing.T, c resource.TestCase) {
+	c.IsUnitTest = testAccMock != nil
+	resource.Test(t, c)
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
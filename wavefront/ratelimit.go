@@ -0,0 +1,108 @@
+package wavefront_plugin
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// requestLimiter bounds the number of API requests in flight, and the rate at which they are sent using a token
+// bucket holding up to a second's worth of requests. One limiter is created for the provider's address and one for
+// each of its cluster blocks, and shared by every resource and data source using it, so it applies across
+// Terraform's parallel operations.
+type requestLimiter struct {
+	// nil when the number of requests in flight is unlimited
+	slots chan struct{}
+
+	mu sync.Mutex
+	// Requests per second, 0 when unlimited
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRequestLimiter(maxConcurrent int, requestsPerSecond float64) *requestLimiter {
+	l := &requestLimiter{rate: requestsPerSecond}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if requestsPerSecond > 0 {
+		l.burst = requestsPerSecond
+		if l.burst < 1 {
+			l.burst = 1
+		}
+		l.tokens = l.burst
+	}
+	return l
+}
+
+// acquire blocks until a request may be sent, returning a func which must be called once it has completed
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if wait := l.reserve(time.Now()); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// reserve takes a token from the bucket, returning how long to wait until it is available
+func (l *requestLimiter) reserve(now time.Time) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	if now.After(l.last) {
+		l.last = now
+	}
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// limitTransport sends each request, including each retry, through a requestLimiter
+type limitTransport struct {
+	next    http.RoundTripper
+	limiter *requestLimiter
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,116 @@
+package wavefront_plugin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestLimiter_maxConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer s.Close()
+
+	client := &http.Client{
+		Transport: &limitTransport{next: http.DefaultTransport, limiter: newRequestLimiter(2, 0)},
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(s.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
+
+func TestRequestLimiter_reserve(t *testing.T) {
+	l := newRequestLimiter(0, 2)
+	now := time.Unix(1570000000, 0)
+
+	// A second's worth of requests may be sent at once, then one every half second
+	cases := []struct {
+		at       time.Duration
+		expected time.Duration
+	}{
+		{0, 0},
+		{0, 0},
+		{0, 500 * time.Millisecond},
+		{0, time.Second},
+		{2 * time.Second, 0},
+		{2 * time.Second, 0},
+		{2 * time.Second, 500 * time.Millisecond},
+		{2250 * time.Millisecond, 750 * time.Millisecond},
+	}
+
+	for i, c := range cases {
+		if wait := l.reserve(now.Add(c.at)); wait != c.expected {
+			t.Errorf("expected request %d to wait %s, got %s", i, c.expected, wait)
+		}
+	}
+}
+
+func TestRequestLimiter_unlimited(t *testing.T) {
+	l := newRequestLimiter(0, 0)
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		release, err := l.acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wait := l.reserve(now); wait != 0 {
+			t.Fatalf("expected no wait without a limit, got %s", wait)
+		}
+		release()
+	}
+}
+
+func TestRequestLimiter_contextCancelled(t *testing.T) {
+	l := newRequestLimiter(1, 0.1)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected waiting for a slot to be cancelled, got %v", err)
+	}
+
+	// The slot is free again, but the next token is ten seconds away
+	release()
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected waiting for a token to be cancelled, got %v", err)
+	}
+	if len(l.slots) != 0 {
+		t.Errorf("expected the slot to be released when the wait is cancelled")
+	}
+}
@@ -1,13 +1,38 @@
 package wavefront_plugin
 
 import (
+	"bytes"
 	"fmt"
+	"log"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/spaceapegames/go-wavefront"
 )
 
+// The severities of a threshold alert, most severe first
+var thresholdSeverities = []string{"severe", "warn", "info", "smoke"}
+
+// The severities of a CLASSIC alert
+var classicSeverities = []string{"SEVERE", "WARN", "INFO", "SMOKE"}
+
+// The attributes each alert type requires, and those it does not support
+var alertTypeFields = map[string]struct {
+	required    []string
+	unsupported []string
+}{
+	wavefront.AlertTypeClassic: {
+		required:    []string{"condition", "severity"},
+		unsupported: []string{"threshold"},
+	},
+	wavefront.AlertTypeThreshold: {
+		required:    []string{"threshold"},
+		unsupported: []string{"condition", "severity", "target"},
+	},
+}
+
 func resourceAlert() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceAlertCreate,
@@ -15,10 +40,23 @@ func resourceAlert() *schema.Resource {
 		Update: resourceAlertUpdate,
 		Delete: resourceAlertDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStateWithCluster,
+		},
+		Timeouts:      resourceTimeouts(),
+		CustomizeDiff: resourceAlertCustomizeDiff,
+
+		// Version 1 replaced the threshold_conditions and threshold_targets maps with threshold blocks
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceAlertV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceAlertStateUpgradeV0,
+			},
 		},
 
 		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(true),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -33,17 +71,43 @@ func resourceAlert() *schema.Resource {
 				Optional: true,
 			},
 			"condition": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				StateFunc: trimSpaces,
+				Type:         schema.TypeString,
+				Optional:     true,
+				StateFunc:    trimSpaces,
+				ValidateFunc: validateQuery,
 			},
-			"threshold_conditions": {
-				Type:     schema.TypeMap,
+			// One block per severity for THRESHOLD alerts
+			"threshold": {
+				Type:     schema.TypeSet,
 				Optional: true,
+				Set:      resourceAlertThresholdHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						// 'severity' must be severe, warn, info or smoke
+						"severity": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateStringIn(thresholdSeverities...),
+						},
+						"condition": {
+							Type:         schema.TypeString,
+							Required:     true,
+							StateFunc:    trimSpaces,
+							ValidateFunc: validateQuery,
+						},
+						"targets": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
 			},
-			"threshold_targets": {
-				Type:     schema.TypeMap,
-				Optional: true,
+			// The severities of the threshold blocks in upper case, most severe first
+			"severity_list": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"additional_information": {
 				Type:      schema.TypeString,
@@ -51,9 +115,10 @@ func resourceAlert() *schema.Resource {
 				StateFunc: trimSpaces,
 			},
 			"display_expression": {
-				Type:      schema.TypeString,
-				Optional:  true,
-				StateFunc: trimSpaces,
+				Type:         schema.TypeString,
+				Optional:     true,
+				StateFunc:    trimSpaces,
+				ValidateFunc: validateQuery,
 			},
 			"minutes": {
 				Type:     schema.TypeInt,
@@ -73,9 +138,10 @@ func resourceAlert() *schema.Resource {
 			},
 			"tags": {
 				Type:     schema.TypeSet,
-				Required: true,
+				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -84,21 +150,175 @@ func trimSpaces(d interface{}) string {
 	return strings.TrimSpace(d.(string))
 }
 
-func trimSpacesMap(m map[string]interface{}) map[string]string {
-	trimmed := map[string]string{}
-	for key, v := range m {
-		trimmed[key] = trimSpaces(v)
+// Hash a threshold block on its trimmed condition so that whitespace differences do not move it within the set
+func resourceAlertThresholdHash(v interface{}) int {
+	var buf bytes.Buffer
+	t := v.(map[string]interface{})
+	buf.WriteString(fmt.Sprintf("%s-", t["severity"].(string)))
+	buf.WriteString(fmt.Sprintf("%s-", trimSpaces(t["condition"])))
+	if targets, ok := t["targets"].(*schema.Set); ok {
+		for _, target := range sortedStrings(targets) {
+			buf.WriteString(fmt.Sprintf("%s-", target))
+		}
 	}
-	return trimmed
+	return hashcode.String(buf.String())
 }
 
-func resourceAlertCreate(d *schema.ResourceData, m interface{}) error {
-	alerts := m.(*wavefrontClient).client.Alerts()
+func sortedStrings(s *schema.Set) []string {
+	strs := []string{}
+	for _, v := range s.List() {
+		strs = append(strs, v.(string))
+	}
+	sort.Strings(strs)
+	return strs
+}
+
+// Convert the threshold blocks into the Conditions and Targets maps used by Wavefront, keyed by severity
+func buildThresholds(thresholds *schema.Set) (map[string]string, map[string]string) {
+	conditions := map[string]string{}
+	targets := map[string]string{}
+	for _, v := range thresholds.List() {
+		t := v.(map[string]interface{})
+		severity := t["severity"].(string)
+		conditions[severity] = trimSpaces(t["condition"])
+		if s, ok := t["targets"].(*schema.Set); ok && s.Len() > 0 {
+			targets[severity] = strings.Join(sortedStrings(s), ",")
+		}
+	}
+	return conditions, targets
+}
+
+// Convert the Conditions and Targets maps of a Wavefront Alert into threshold blocks
+func buildTerraformThresholds(conditions map[string]string, targets map[string]string) []map[string]interface{} {
+	thresholds := []map[string]interface{}{}
+	for _, severity := range thresholdSeverities {
+		condition, ok := conditions[severity]
+		if !ok {
+			continue
+		}
+		threshold := map[string]interface{}{
+			"severity":  severity,
+			"condition": trimSpaces(condition),
+		}
+		ts := []string{}
+		for _, target := range strings.Split(targets[severity], ",") {
+			if target = strings.TrimSpace(target); target != "" {
+				ts = append(ts, target)
+			}
+		}
+		threshold["targets"] = ts
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds
+}
+
+// The severities of the given conditions, in upper case and ordered most severe first
+func thresholdSeverityList(conditions map[string]string) []string {
+	severityList := []string{}
+	for _, severity := range thresholdSeverities {
+		if _, ok := conditions[severity]; ok {
+			severityList = append(severityList, strings.ToUpper(severity))
+		}
+	}
+	return severityList
+}
+
+// Validate the fields set for the alert type, reject duplicate threshold severities and derive severity_list at plan time
+func resourceAlertCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	err := validateAlertTypeFields(d)
+	if err != nil {
+		return err
+	}
+
+	conditions := map[string]string{}
+	for _, v := range d.Get("threshold").(*schema.Set).List() {
+		severity := v.(map[string]interface{})["severity"].(string)
+		if _, ok := conditions[severity]; ok {
+			return fmt.Errorf("threshold: severity %s is set more than once", severity)
+		}
+		conditions[severity] = ""
+	}
+
+	err = setComputedStrings(d, "severity_list", thresholdSeverityList(conditions))
+	if err != nil {
+		return err
+	}
+
+	return customizeDiffTagsAll(d, setStrings(d.Get("tags")), d.NewValueKnown("tags"), m)
+}
+
+// Check the attributes set against those allowed for the alert type. Attributes whose value is not yet known
+// are assumed to be set.
+func validateAlertTypeFields(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("alert_type") {
+		return nil
+	}
+	alertType := d.Get("alert_type").(string)
+	fields, ok := alertTypeFields[alertType]
+	if !ok {
+		return fmt.Errorf("alert_type must be CLASSIC or THRESHOLD, got %q", alertType)
+	}
 
-	var tags []string
-	for _, tag := range d.Get("tags").(*schema.Set).List() {
-		tags = append(tags, tag.(string))
+	var errs []string
+	for _, key := range fields.required {
+		if !alertFieldSet(d, key) {
+			errs = append(errs, fmt.Sprintf("%s must be supplied for %s alerts", key, strings.ToLower(alertType)))
+		}
+	}
+	for _, key := range fields.unsupported {
+		if alertFieldSet(d, key) {
+			errs = append(errs, fmt.Sprintf("%s cannot be set for %s alerts", key, strings.ToLower(alertType)))
+		}
+	}
+	if alertType == wavefront.AlertTypeClassic && d.NewValueKnown("severity") && d.Get("severity") != "" {
+		if _, es := validateStringIn(classicSeverities...)(d.Get("severity"), "severity"); len(es) > 0 {
+			errs = append(errs, es[0].Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid %s alert %q: %s", alertType, d.Get("name"), strings.Join(errs, ", "))
+	}
+
+	// This SDK has no way to return a warning from CustomizeDiff, and an attribute's ValidateFunc can't see the
+	// alert_type, so an alert without targets is only written to the log, which is shown with TF_LOG=WARN
+	if !alertHasTargets(d, alertType) {
+		log.Printf("[WARN] %s alert %q has no targets, no notifications will be sent when it fires", alertType, d.Get("name"))
+	}
+	return nil
+}
+
+func alertFieldSet(d *schema.ResourceDiff, key string) bool {
+	if !d.NewValueKnown(key) {
+		return true
+	}
+	_, ok := d.GetOk(key)
+	return ok
+}
+
+func alertHasTargets(d *schema.ResourceDiff, alertType string) bool {
+	if alertType == wavefront.AlertTypeClassic {
+		return alertFieldSet(d, "target")
 	}
+	if !d.NewValueKnown("threshold") {
+		return true
+	}
+	for _, v := range d.Get("threshold").(*schema.Set).List() {
+		if targets, ok := v.(map[string]interface{})["targets"].(*schema.Set); ok && targets.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceAlertCreate(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	alerts := c.client.Alerts()
+
+	tags := mergeTags(setStrings(d.Get("tags")), defaultTagsOf(m))
 
 	a := &wavefront.Alert{
 		Name:                               d.Get("name").(string),
@@ -110,7 +330,7 @@ func resourceAlertCreate(d *schema.ResourceData, m interface{}) error {
 		Tags:                               tags,
 	}
 
-	err := validateAlertConditions(a, d)
+	err = validateAlertConditions(a, d)
 	if err != nil {
 		return err
 	}
@@ -118,26 +338,35 @@ func resourceAlertCreate(d *schema.ResourceData, m interface{}) error {
 	// Create the alert on Wavefront
 	err = alerts.Create(a)
 	if err != nil {
-		return fmt.Errorf("error creating Alert %s. %s", d.Get("name"), err)
+		return wavefrontError("creating", "Alert", d.Get("name"), err)
 	}
 
 	d.SetId(*a.ID)
+	d.Set("tags_all", tags)
 
 	return nil
 }
 
 func resourceAlertRead(d *schema.ResourceData, m interface{}) error {
-	alerts := m.(*wavefrontClient).client.Alerts()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	alerts := c.client.Alerts()
 
 	alertID := d.Id()
 	tmpAlert := wavefront.Alert{ID: &alertID}
-	err := alerts.Get(&tmpAlert)
+	err = c.cache.read(&c.client, "alert", alertID, &tmpAlert, func() error {
+		return alerts.Get(&tmpAlert)
+	})
+	if isNotFound(err) {
+		log.Printf("[WARN] Wavefront Alert %s not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			d.SetId("")
-		} else {
-			return fmt.Errorf("error finding Wavefront Alert %s. %s", d.Id(), err)
-		}
+		return wavefrontError("reading", "Alert", d.Id(), err)
 	}
 
 	// Use the Wavefront ID as the Terraform ID
@@ -151,29 +380,31 @@ func resourceAlertRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("resolve_after_minutes", tmpAlert.ResolveAfterMinutes)
 	d.Set("notification_resend_frequency_minutes", tmpAlert.NotificationResendFrequencyMinutes)
 	d.Set("severity", tmpAlert.Severity)
-	d.Set("tags", tmpAlert.Tags)
+	d.Set("tags", withoutDefaultTags(tmpAlert.Tags, setStrings(d.Get("tags")), defaultTagsOf(m)))
+	d.Set("tags_all", tmpAlert.Tags)
 	d.Set("alert_type", tmpAlert.AlertType)
-	d.Set("threshold_conditions", tmpAlert.Conditions)
-	d.Set("threshold_targets", tmpAlert.Targets)
+	d.Set("threshold", buildTerraformThresholds(tmpAlert.Conditions, tmpAlert.Targets))
+	d.Set("severity_list", thresholdSeverityList(tmpAlert.Conditions))
 
 	return nil
 }
 
 func resourceAlertUpdate(d *schema.ResourceData, m interface{}) error {
-	alerts := m.(*wavefrontClient).client.Alerts()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	alerts := c.client.Alerts()
 
 	alertID := d.Id()
 	tmpAlert := wavefront.Alert{ID: &alertID}
-	err := alerts.Get(&tmpAlert)
-
+	err = alerts.Get(&tmpAlert)
 	if err != nil {
-		return fmt.Errorf("Error finding Wavefront Alert %s. %s", d.Id(), err)
+		return wavefrontError("reading", "Alert", d.Id(), err)
 	}
 
-	var tags []string
-	for _, tag := range d.Get("tags").(*schema.Set).List() {
-		tags = append(tags, tag.(string))
-	}
+	tags := mergeTags(setStrings(d.Get("tags")), defaultTagsOf(m))
 
 	a := tmpAlert
 	a.Name = d.Get("name").(string)
@@ -190,28 +421,41 @@ func resourceAlertUpdate(d *schema.ResourceData, m interface{}) error {
 	}
 
 	// Update the alert on Wavefront
+	c.cache.evict("alert", d.Id())
 	err = alerts.Update(&a)
 	if err != nil {
-		return fmt.Errorf("Error Updating Alert %s. %s", d.Get("name"), err)
+		return wavefrontError("updating", "Alert", d.Id(), err)
 	}
+	d.Set("tags_all", tags)
 	return nil
 }
 
 func resourceAlertDelete(d *schema.ResourceData, m interface{}) error {
-	alerts := m.(*wavefrontClient).client.Alerts()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	alerts := c.client.Alerts()
 
 	alertID := d.Id()
 	tmpAlert := wavefront.Alert{ID: &alertID}
-	err := alerts.Get(&tmpAlert)
+	err = alerts.Get(&tmpAlert)
+	if isNotFound(err) {
+		log.Printf("[WARN] Wavefront Alert %s already deleted", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("Error finding Wavefront Alert %s. %s", d.Id(), err)
+		return wavefrontError("reading", "Alert", d.Id(), err)
 	}
 	a := tmpAlert
 
 	// Delete the Alert
+	c.cache.evict("alert", d.Id())
 	err = alerts.Delete(&a)
 	if err != nil {
-		return fmt.Errorf("Failed to delete Alert %s. %s", d.Id(), err)
+		return wavefrontError("deleting", "Alert", d.Id(), err)
 	}
 	d.SetId("")
 	return nil
@@ -220,20 +464,12 @@ func resourceAlertDelete(d *schema.ResourceData, m interface{}) error {
 func validateAlertConditions(a *wavefront.Alert, d *schema.ResourceData) error {
 	if d.Get("alert_type") == wavefront.AlertTypeThreshold {
 		a.AlertType = wavefront.AlertTypeThreshold
-		if conditions, ok := d.GetOk("threshold_conditions"); ok {
-			a.Conditions = trimSpacesMap(conditions.(map[string]interface{}))
-			err := validateThresholdLevels(a.Conditions)
-			if err != nil {
-				return err
-			}
-		} else {
-			return fmt.Errorf("threshold_conditions must be supplied for threshold alerts")
-		}
-
-		if targets, ok := d.GetOk("threshold_targets"); ok {
-			a.Targets = trimSpacesMap(targets.(map[string]interface{}))
-			return validateThresholdLevels(a.Targets)
+		thresholds, ok := d.GetOk("threshold")
+		if !ok {
+			return fmt.Errorf("threshold must be supplied for threshold alerts")
 		}
+		a.Conditions, a.Targets = buildThresholds(thresholds.(*schema.Set))
+		a.SeverityList = thresholdSeverityList(a.Conditions)
 
 	} else if d.Get("alert_type") == wavefront.AlertTypeClassic {
 		a.AlertType = wavefront.AlertTypeClassic
@@ -254,19 +490,3 @@ func validateAlertConditions(a *wavefront.Alert, d *schema.ResourceData) error {
 
 	return nil
 }
-
-func validateThresholdLevels(m map[string]string) error {
-	for key := range m {
-		ok := false
-		for _, level := range []string{"severe", "warn", "info", "smoke"} {
-			if key == level {
-				ok = true
-				break
-			}
-		}
-		if !ok {
-			return fmt.Errorf("invalid severity: %s", key)
-		}
-	}
-	return nil
-}
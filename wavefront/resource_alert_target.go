@@ -2,10 +2,10 @@ package wavefront_plugin
 
 import (
 	"fmt"
+	"log"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/spaceapegames/go-wavefront"
-	"strings"
 )
 
 func resourceTarget() *schema.Resource {
@@ -15,10 +15,12 @@ func resourceTarget() *schema.Resource {
 		Update: resourceTargetUpdate,
 		Delete: resourceTargetDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStateWithCluster,
 		},
+		Timeouts: resourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(true),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -70,7 +72,12 @@ func resourceTarget() *schema.Resource {
 }
 
 func resourceTargetCreate(d *schema.ResourceData, m interface{}) error {
-	targets := m.(*wavefrontClient).client.Targets()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	targets := c.client.Targets()
 
 	var triggers []string
 	for _, trigger := range d.Get("triggers").([]interface{}) {
@@ -96,9 +103,9 @@ func resourceTargetCreate(d *schema.ResourceData, m interface{}) error {
 	}
 
 	// Create the Target on Wavefront
-	err := targets.Create(t)
+	err = targets.Create(t)
 	if err != nil {
-		return fmt.Errorf("Error Creating Target %s. %s", d.Get("name"), err)
+		return wavefrontError("creating", "Alert Target", d.Get("name"), err)
 	}
 
 	d.SetId(*t.ID)
@@ -107,17 +114,25 @@ func resourceTargetCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTargetRead(d *schema.ResourceData, m interface{}) error {
-	targets := m.(*wavefrontClient).client.Targets()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	targets := c.client.Targets()
 
 	targetID := d.Id()
 	tmpTarget := wavefront.Target{ID: &targetID}
-	err := targets.Get(&tmpTarget)
+	err = c.cache.read(&c.client, "notificant", targetID, &tmpTarget, func() error {
+		return targets.Get(&tmpTarget)
+	})
+	if isNotFound(err) {
+		log.Printf("[WARN] Wavefront Alert Target %s not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			d.SetId("")
-		} else {
-			return fmt.Errorf("error finding Wavefront Alert Target %s. %s", d.Id(), err)
-		}
+		return wavefrontError("reading", "Alert Target", d.Id(), err)
 	}
 
 	// Use the Wavefront ID as the Terraform ID
@@ -137,9 +152,14 @@ func resourceTargetRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTargetUpdate(d *schema.ResourceData, m interface{}) error {
-	targets := m.(*wavefrontClient).client.Targets()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	targets := c.client.Targets()
 
-	results, err := targets.Find(
+	results, err := c.findTargets(
 		[]*wavefront.SearchCondition{
 			{
 				Key:            "id",
@@ -148,7 +168,10 @@ func resourceTargetUpdate(d *schema.ResourceData, m interface{}) error {
 			},
 		})
 	if err != nil {
-		return fmt.Errorf("Error finding Wavefront Target %s. %s", d.Id(), err)
+		return wavefrontError("reading", "Alert Target", d.Id(), err)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("error reading Wavefront Alert Target %s. it does not exist", d.Id())
 	}
 
 	var triggers []string
@@ -174,17 +197,23 @@ func resourceTargetUpdate(d *schema.ResourceData, m interface{}) error {
 	t.CustomHeaders = customHeaders
 
 	// Update the Target on Wavefront
+	c.cache.evict("notificant", d.Id())
 	err = targets.Update(t)
 	if err != nil {
-		return fmt.Errorf("Error Updating Target %s. %s", d.Get("name"), err)
+		return wavefrontError("updating", "Alert Target", d.Id(), err)
 	}
 	return nil
 }
 
 func resourceTargetDelete(d *schema.ResourceData, m interface{}) error {
-	targets := m.(*wavefrontClient).client.Targets()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	targets := c.client.Targets()
 
-	results, err := targets.Find(
+	results, err := c.findTargets(
 		[]*wavefront.SearchCondition{
 			&wavefront.SearchCondition{
 				Key:            "id",
@@ -193,14 +222,20 @@ func resourceTargetDelete(d *schema.ResourceData, m interface{}) error {
 			},
 		})
 	if err != nil {
-		return fmt.Errorf("Error finding Wavefront Target %s. %s", d.Id(), err)
+		return wavefrontError("reading", "Alert Target", d.Id(), err)
+	}
+	if len(results) == 0 {
+		log.Printf("[WARN] Wavefront Alert Target %s already deleted", d.Id())
+		d.SetId("")
+		return nil
 	}
 	t := results[0]
 
 	// Delete the Target
+	c.cache.evict("notificant", d.Id())
 	err = targets.Delete(t)
 	if err != nil {
-		return fmt.Errorf("Failed to delete Target %s. %s", d.Id(), err)
+		return wavefrontError("deleting", "Alert Target", d.Id(), err)
 	}
 	d.SetId("")
 	return nil
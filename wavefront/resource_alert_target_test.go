@@ -12,7 +12,7 @@ import (
 func TestAccWavefrontTarget_BasicWebhook(t *testing.T) {
 	var record wavefront.Target
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontTargetDestroy,
@@ -54,7 +54,7 @@ func TestAccWavefrontTarget_BasicWebhook(t *testing.T) {
 func TestAccWavefrontTarget_Updated(t *testing.T) {
 	var record wavefront.Target
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontTargetDestroy,
@@ -86,7 +86,7 @@ func TestAccWavefrontTarget_Updated(t *testing.T) {
 func TestAccWavefrontTarget_BasicEmail(t *testing.T) {
 	var record wavefront.Target
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontTargetDestroy,
@@ -127,7 +127,7 @@ func TestAccWavefrontTarget_BasicEmail(t *testing.T) {
 func TestAccWavefrontTarget_BasicPagerduty(t *testing.T) {
 	var record wavefront.Target
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontTargetDestroy,
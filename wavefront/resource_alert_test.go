@@ -3,6 +3,9 @@ package wavefront_plugin
 import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -13,7 +16,7 @@ import (
 func TestAccWavefrontAlert_Basic(t *testing.T) {
 	var record wavefront.Alert
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontAlertDestroy,
@@ -52,7 +55,7 @@ func TestAccWavefrontAlert_Basic(t *testing.T) {
 func TestAccWavefrontAlert_RequiredAttributes(t *testing.T) {
 	var record wavefront.Alert
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontAlertDestroy,
@@ -87,7 +90,7 @@ func TestAccWavefrontAlert_RequiredAttributes(t *testing.T) {
 func TestAccWavefrontAlert_Updated(t *testing.T) {
 	var record wavefront.Alert
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontAlertDestroy,
@@ -117,7 +120,7 @@ func TestAccWavefrontAlert_Updated(t *testing.T) {
 func TestAccWavefrontAlert_RemoveOptionalAttribute(t *testing.T) {
 	var record wavefront.Alert
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontAlertDestroy,
@@ -147,7 +150,7 @@ func TestAccWavefrontAlert_RemoveOptionalAttribute(t *testing.T) {
 func TestAccWavefrontAlert_Multiple(t *testing.T) {
 	var record wavefront.Alert
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontAlertDestroy,
@@ -172,7 +175,7 @@ func TestAccWavefrontAlert_Multiple(t *testing.T) {
 func TestAccWavefrontAlert_Threshold(t *testing.T) {
 	var record wavefront.Alert
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontAlertDestroy,
@@ -185,15 +188,156 @@ func TestAccWavefrontAlert_Threshold(t *testing.T) {
 
 					//Check against state that the attributes are as we expect
 					resource.TestCheckResourceAttr(
-						"wavefront_alert.test_threshold_alert", "threshold_conditions.%", "3"),
+						"wavefront_alert.test_threshold_alert", "threshold.#", "3"),
 					resource.TestCheckResourceAttr(
-						"wavefront_alert.test_threshold_alert", "threshold_targets.%", "1"),
+						"wavefront_alert.test_threshold_alert", "severity_list.#", "3"),
+					resource.TestCheckResourceAttr(
+						"wavefront_alert.test_threshold_alert", "severity_list.0", "SEVERE"),
+					resource.TestCheckResourceAttr(
+						"wavefront_alert.test_threshold_alert", "severity_list.1", "WARN"),
+					resource.TestCheckResourceAttr(
+						"wavefront_alert.test_threshold_alert", "severity_list.2", "INFO"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccWavefrontAlert_ThresholdInvalidSeverity(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckWavefrontAlert_thresholdSeverity("sever", "warn"),
+				ExpectError: regexp.MustCompile(`threshold.0.severity must be one of severe, warn, info, smoke, got "sever"`),
+			},
+			{
+				Config:      testAccCheckWavefrontAlert_thresholdSeverity("warn", "warn"),
+				ExpectError: regexp.MustCompile("threshold: severity warn is set more than once"),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontAlert_InvalidFieldCombinations(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckWavefrontAlert_fields(`alert_type = "CLASSIC"`, `condition = "ts(cpu) > 80"`),
+				ExpectError: regexp.MustCompile(`invalid CLASSIC alert "Terraform Test Alert Fields": severity must be supplied for classic alerts`),
+			},
+			{
+				Config:      testAccCheckWavefrontAlert_fields(`alert_type = "CLASSIC"`, `condition = "ts(cpu) > 80"`, `severity = "warn"`),
+				ExpectError: regexp.MustCompile(`invalid CLASSIC alert "Terraform Test Alert Fields": severity must be one of SEVERE, WARN, INFO, SMOKE, got "warn"`),
+			},
+			{
+				Config: testAccCheckWavefrontAlert_fields(`alert_type = "CLASSIC"`, `condition = "ts(cpu) > 80"`, `severity = "WARN"`,
+					`threshold {
+    severity = "warn"
+    condition = "ts(cpu) > 80"
+  }`),
+				ExpectError: regexp.MustCompile(`invalid CLASSIC alert "Terraform Test Alert Fields": threshold cannot be set for classic alerts`),
+			},
+			{
+				Config:      testAccCheckWavefrontAlert_fields(`alert_type = "THRESHOLD"`),
+				ExpectError: regexp.MustCompile(`invalid THRESHOLD alert "Terraform Test Alert Fields": threshold must be supplied for threshold alerts`),
+			},
+			{
+				Config: testAccCheckWavefrontAlert_fields(`alert_type = "THRESHOLD"`, `condition = "ts(cpu) > 80"`, `severity = "WARN"`, `target = "test@example.com"`,
+					`threshold {
+    severity = "warn"
+    condition = "ts(cpu) > 80"
+  }`),
+				ExpectError: regexp.MustCompile(`condition cannot be set for threshold alerts, severity cannot be set for threshold alerts, target cannot be set for threshold alerts`),
+			},
+			{
+				Config:      testAccCheckWavefrontAlert_fields(`alert_type = "BANANA"`),
+				ExpectError: regexp.MustCompile(`alert_type must be CLASSIC or THRESHOLD, got "BANANA"`),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontAlert_InvalidQuery(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckWavefrontAlert_fields(`condition = "sume(ts(cpu.load)) > 80"`, `severity = "WARN"`),
+				ExpectError: regexp.MustCompile(`condition is not a valid Wavefront query: line 1, column 1: unknown function "sume", did you mean "sum"\?`),
+			},
+			{
+				Config:      testAccCheckWavefrontAlert_fields(`condition = "sum(ts(cpu.load)) >"`, `severity = "WARN"`),
+				ExpectError: regexp.MustCompile(`condition is not a valid Wavefront query: line 1, column 20: unexpected end of query, expected an expression`),
+			},
+			{
+				Config: testAccCheckWavefrontAlert_fields(`alert_type = "THRESHOLD"`,
+					`threshold {
+    severity = "warn"
+    condition = "ts(cpu.load > 80"
+  }`),
+				ExpectError: regexp.MustCompile(`threshold.0.condition is not a valid Wavefront query: line 1, column 13: unexpected ">"`),
+			},
+		},
+	})
+}
+
+func TestResourceAlert_thresholds(t *testing.T) {
+	thresholds := schema.NewSet(resourceAlertThresholdHash, []interface{}{
+		map[string]interface{}{
+			"severity":  "warn",
+			"condition": " ts(cpu) > 60 ",
+			"targets":   schema.NewSet(schema.HashString, []interface{}{"target:b", "target:a"}),
+		},
+		map[string]interface{}{
+			"severity":  "severe",
+			"condition": "ts(cpu) > 80",
+			"targets":   schema.NewSet(schema.HashString, []interface{}{}),
+		},
+	})
+
+	conditions, targets := buildThresholds(thresholds)
+	expectedConditions := map[string]string{"warn": "ts(cpu) > 60", "severe": "ts(cpu) > 80"}
+	if !reflect.DeepEqual(conditions, expectedConditions) {
+		t.Errorf("expected conditions %v, got %v", expectedConditions, conditions)
+	}
+	expectedTargets := map[string]string{"warn": "target:a,target:b"}
+	if !reflect.DeepEqual(targets, expectedTargets) {
+		t.Errorf("expected targets %v, got %v", expectedTargets, targets)
+	}
+
+	severityList := thresholdSeverityList(conditions)
+	if !reflect.DeepEqual(severityList, []string{"SEVERE", "WARN"}) {
+		t.Errorf("expected severity list [SEVERE WARN], got %v", severityList)
+	}
+
+	terraformThresholds := buildTerraformThresholds(conditions, targets)
+	if len(terraformThresholds) != 2 {
+		t.Fatalf("expected 2 thresholds, got %v", terraformThresholds)
+	}
+	if terraformThresholds[0]["severity"] != "severe" || len(terraformThresholds[0]["targets"].([]string)) != 0 {
+		t.Errorf("unexpected severe threshold %v", terraformThresholds[0])
+	}
+	if !reflect.DeepEqual(terraformThresholds[1]["targets"], []string{"target:a", "target:b"}) {
+		t.Errorf("unexpected warn threshold %v", terraformThresholds[1])
+	}
+}
+
+func TestResourceAlert_thresholdHashIgnoresWhitespace(t *testing.T) {
+	a := map[string]interface{}{"severity": "severe", "condition": "ts(cpu) > 80"}
+	b := map[string]interface{}{"severity": "severe", "condition": "\n  ts(cpu) > 80\n"}
+	if resourceAlertThresholdHash(a) != resourceAlertThresholdHash(b) {
+		t.Errorf("expected thresholds differing only by whitespace to hash the same")
+	}
+}
+
 func TestResourceAlert_validateAlertConditions(t *testing.T) {
 
 	cases := []struct {
@@ -248,40 +392,20 @@ func TestResourceAlert_validateAlertConditions(t *testing.T) {
 				d.Set("alert_type", "THRESHOLD")
 				return d
 			}(),
-			"threshold_conditions must be supplied for threshold alerts",
+			"threshold must be supplied for threshold alerts",
 		},
 		{
 			"threshold alert",
 			func() *schema.ResourceData {
 				d := resourceAlert().TestResourceData()
 				d.Set("alert_type", "THRESHOLD")
-				d.Set("threshold_conditions", map[string]interface{}{"severe": "ts()"})
+				d.Set("threshold", []interface{}{
+					map[string]interface{}{"severity": "severe", "condition": "ts()"},
+				})
 				return d
 			}(),
 			"",
 		},
-		{
-			"threshold alert invalid condition",
-			func() *schema.ResourceData {
-				d := resourceAlert().TestResourceData()
-				d.Set("alert_type", "THRESHOLD")
-				d.Set("threshold_conditions", map[string]interface{}{"banana": "ts()"})
-				return d
-			}(),
-			"invalid severity: banana",
-		},
-		{
-			"threshold alert invalid target",
-			func() *schema.ResourceData {
-				d := resourceAlert().TestResourceData()
-				d.Set("alert_type", "THRESHOLD")
-				d.Set("threshold_conditions", map[string]interface{}{"severe": "ts()"})
-				d.Set("threshold_targets", map[string]interface{}{"banana": "ts()"})
-
-				return d
-			}(),
-			"invalid severity: banana",
-		},
 	}
 
 	for _, c := range cases {
@@ -570,19 +694,64 @@ resource "wavefront_alert" "test_threshold_alert" {
   minutes = 5
   resolve_after_minutes = 5
 
-  threshold_conditions = {
-    "severe" = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
-    "warn" = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 60"
-    "info" = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 50"
+  threshold {
+    severity = "severe"
+    condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 80"
+    targets = [
+      "target:${wavefront_alert_target.test_target.id}"
+    ]
   }
 
-  threshold_targets = {
-	"severe" = "target:${wavefront_alert_target.test_target.id}"
+  threshold {
+    severity = "warn"
+    condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 60"
   }
-  
+
+  threshold {
+    severity = "info"
+    condition = "100-ts(\"cpu.usage_idle\", environment=preprod and cpu=cpu-total ) > 50"
+  }
+
   tags = [
     "terraform"
   ]
 }
 `)
 }
+
+func testAccCheckWavefrontAlert_thresholdSeverity(first, second string) string {
+	return fmt.Sprintf(`
+resource "wavefront_alert" "test_threshold_alert" {
+  name = "Terraform Test Alert"
+  alert_type = "THRESHOLD"
+  minutes = 5
+
+  threshold {
+    severity = "%s"
+    condition = "ts(\"cpu.usage_idle\") < 10"
+  }
+
+  threshold {
+    severity = "%s"
+    condition = "ts(\"cpu.usage_idle\") < 20"
+  }
+
+  tags = [
+    "terraform"
+  ]
+}
+`, first, second)
+}
+
+func testAccCheckWavefrontAlert_fields(fields ...string) string {
+	return fmt.Sprintf(`
+resource "wavefront_alert" "test_alert_fields" {
+  name = "Terraform Test Alert Fields"
+  minutes = 5
+  %s
+  tags = [
+    "terraform"
+  ]
+}
+`, strings.Join(fields, "\n  "))
+}
@@ -0,0 +1,101 @@
+package wavefront_plugin
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// resourceAlertV0 is the schema of wavefront_alert before the threshold_conditions and threshold_targets maps were
+// replaced by threshold blocks. Only its type is used, to decode the state being upgraded.
+func resourceAlertV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"alert_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  wavefront.AlertTypeClassic,
+			},
+			"target": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"condition": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"threshold_conditions": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"threshold_targets": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"additional_information": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"display_expression": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"minutes": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"resolve_after_minutes": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"notification_resend_frequency_minutes": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"severity": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// resourceAlertStateUpgradeV0 moves the threshold_conditions and threshold_targets maps of a version 0 state into
+// one threshold block per severity. Targets of a severity without a condition are dropped, as a threshold block
+// needs a condition and Wavefront doesn't notify them.
+func resourceAlertStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	conditions := stringMap(rawState["threshold_conditions"])
+	targets := stringMap(rawState["threshold_targets"])
+	delete(rawState, "threshold_conditions")
+	delete(rawState, "threshold_targets")
+
+	thresholds := []interface{}{}
+	for _, t := range buildTerraformThresholds(conditions, targets) {
+		thresholds = append(thresholds, t)
+	}
+	rawState["threshold"] = thresholds
+	return rawState, nil
+}
+
+// stringMap converts a map attribute of a JSON encoded state into a map of strings keyed in lower case, as
+// severities are
+func stringMap(v interface{}) map[string]string {
+	m := map[string]string{}
+	raw, _ := v.(map[string]interface{})
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			m[strings.ToLower(k)] = s
+		}
+	}
+	return m
+}
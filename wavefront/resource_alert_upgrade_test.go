@@ -0,0 +1,91 @@
+package wavefront_plugin
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestResourceAlertStateUpgradeV0(t *testing.T) {
+	v0 := map[string]interface{}{
+		"id":         "1234",
+		"name":       "Terraform Test Alert",
+		"alert_type": "THRESHOLD",
+		"minutes":    5,
+		"threshold_conditions": map[string]interface{}{
+			"severe": "ts(cpu.load) > 90",
+			"WARN":   " ts(cpu.load) > 80 ",
+		},
+		"threshold_targets": map[string]interface{}{
+			"severe": "pd:abc, target:def",
+			// A target without a condition is dropped
+			"info": "test@example.com",
+		},
+	}
+
+	v1, err := resourceAlertStateUpgradeV0(v0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"id":         "1234",
+		"name":       "Terraform Test Alert",
+		"alert_type": "THRESHOLD",
+		"minutes":    5,
+		"threshold": []interface{}{
+			map[string]interface{}{
+				"severity":  "severe",
+				"condition": "ts(cpu.load) > 90",
+				"targets":   []string{"pd:abc", "target:def"},
+			},
+			map[string]interface{}{
+				"severity":  "warn",
+				"condition": "ts(cpu.load) > 80",
+				"targets":   []string{},
+			},
+		},
+	}
+	if !reflect.DeepEqual(v1, expected) {
+		t.Fatalf("expected the upgraded state %v, got %v", expected, v1)
+	}
+
+	// The upgraded state fits the current schema and describes the same thresholds
+	d := schema.TestResourceDataRaw(t, resourceAlert().Schema, map[string]interface{}{"threshold": v1["threshold"]})
+	conditions, targets := buildThresholds(d.Get("threshold").(*schema.Set))
+	if !reflect.DeepEqual(conditions, map[string]string{"severe": "ts(cpu.load) > 90", "warn": "ts(cpu.load) > 80"}) {
+		t.Errorf("unexpected conditions %v", conditions)
+	}
+	if !reflect.DeepEqual(targets, map[string]string{"severe": "pd:abc,target:def"}) {
+		t.Errorf("unexpected targets %v", targets)
+	}
+}
+
+func TestResourceAlertStateUpgradeV0_classic(t *testing.T) {
+	v1, err := resourceAlertStateUpgradeV0(map[string]interface{}{
+		"id":        "1234",
+		"condition": "ts(cpu.load) > 80",
+		"severity":  "WARN",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thresholds := v1["threshold"].([]interface{}); len(thresholds) != 0 {
+		t.Errorf("expected a classic alert to have no threshold blocks, got %v", thresholds)
+	}
+	if v1["condition"] != "ts(cpu.load) > 80" || v1["severity"] != "WARN" {
+		t.Errorf("expected the other attributes to be kept, got %v", v1)
+	}
+}
+
+func TestResourceAlert_schemaVersion(t *testing.T) {
+	r := resourceAlert()
+	if err := r.InternalValidate(nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if r.SchemaVersion != 1 || len(r.StateUpgraders) != 1 || r.StateUpgraders[0].Version != 0 {
+		t.Errorf("expected a state upgrader from version 0 to 1, got version %d with %d upgraders",
+			r.SchemaVersion, len(r.StateUpgraders))
+	}
+}
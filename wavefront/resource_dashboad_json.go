@@ -5,7 +5,7 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/spaceapegames/go-wavefront"
 	"log"
-	"strings"
+	"sort"
 )
 
 func resourceDashboardJson() *schema.Resource {
@@ -15,22 +15,47 @@ func resourceDashboardJson() *schema.Resource {
 		Update: resourceDashboardJsonUpdate,
 		Delete: resourceDashboardJsonDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStateWithCluster,
 		},
+		Timeouts:      resourceTimeouts(),
+		CustomizeDiff: resourceDashboardJsonCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(true),
 			"dashboard_json": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: ValidateDashboardJson,
-				StateFunc:    NormalizeDashboardJson,
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     ValidateDashboardJson,
+				StateFunc:        NormalizeDashboardJson,
+				DiffSuppressFunc: suppressEquivalentDashboardJson,
 			},
+			// Paths in dashboard_json, such as parameterDetails.*.defaultValue, which are left as they are in
+			// Wavefront. They are ignored by plans and keep Wavefront's values on update.
+			"ignore_paths": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateJsonPath,
+				},
+			},
+			"tags_all": tagsAllSchema(),
 		},
 	}
 
 }
 
-func buildDashboardJson(d *schema.ResourceData) (*wavefront.Dashboard, error) {
+// Plan tags_all as the tags of the dashboard_json merged with the provider's default tags
+func resourceDashboardJsonCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	var dashboard wavefront.Dashboard
+	known := d.NewValueKnown("dashboard_json")
+	if known {
+		_ = dashboard.UnmarshalJSON([]byte(d.Get("dashboard_json").(string)))
+	}
+	return customizeDiffTagsAll(d, dashboard.Tags, known, m)
+}
+
+func buildDashboardJson(d *schema.ResourceData, defaultTags []string) (*wavefront.Dashboard, error) {
 	var dashboard wavefront.Dashboard
 	dashboardJsonString := d.Get("dashboard_json").(string)
 	// json is already validated during resource Validation
@@ -38,23 +63,41 @@ func buildDashboardJson(d *schema.ResourceData) (*wavefront.Dashboard, error) {
 
 	// set url name as the resource ID
 	dashboard.ID = dashboard.Url
+	dashboard.Tags = mergeTags(dashboard.Tags, defaultTags)
 	return &dashboard, nil
 }
 
 func resourceDashboardJsonRead(d *schema.ResourceData, m interface{}) error {
-	dashboards := m.(*wavefrontClient).client.Dashboards()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	dashboards := c.client.Dashboards()
 	dash := wavefront.Dashboard{
 		ID: d.Id(),
 	}
-	err := dashboards.Get(&dash)
+	err = c.cache.read(&c.client, "dashboard", dash.ID, &dash, func() error {
+		return dashboards.Get(&dash)
+	})
+	if isNotFound(err) {
+		log.Printf("[WARN] Wavefront Dashboard %s not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			d.SetId("")
-		} else {
-			return fmt.Errorf("error finding Wavefront Dashboard %s. %s", d.Id(), err)
-		}
+		return wavefrontError("reading", "Dashboard", d.Id(), err)
 	}
+	// The default tags are kept out of dashboard_json, unless it configures them too
+	var configured wavefront.Dashboard
+	_ = configured.UnmarshalJSON([]byte(d.Get("dashboard_json").(string)))
+	d.Set("tags_all", dash.Tags)
+	dash.Tags = withoutDefaultTags(dash.Tags, configured.Tags, defaultTagsOf(m))
+
 	bytes, err := dash.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal dashboard json %s. %s", dash.ID, err)
+	}
 	// Use the Wavefront url as the Terraform ID
 	d.SetId(dash.ID)
 	err = d.Set("dashboard_json", NormalizeDashboardJson(string(bytes)))
@@ -66,16 +109,22 @@ func resourceDashboardJsonRead(d *schema.ResourceData, m interface{}) error {
 
 func resourceDashboardJsonCreate(d *schema.ResourceData, m interface{}) error {
 	log.Printf("[INFO] Create Wavefront Dashboard %s", d.Id())
-	dashboards := m.(*wavefrontClient).client.Dashboards()
-	dashboard, err := buildDashboardJson(d)
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	dashboards := c.client.Dashboards()
+	dashboard, err := buildDashboardJson(d, defaultTagsOf(m))
 
 	if err != nil {
 		return fmt.Errorf("failed to parse dashboard, %s", err)
 	}
 
+	c.cache.evict("dashboard", dashboard.ID)
 	err = dashboards.Create(dashboard)
 	if err != nil {
-		return fmt.Errorf("failed to create dashboard, %s", err)
+		return wavefrontError("creating", "Dashboard", dashboard.Url, err)
 	}
 	d.SetId(dashboard.ID)
 	log.Printf("[INFO] Wavefront Dashboard %s Created", d.Id())
@@ -84,37 +133,90 @@ func resourceDashboardJsonCreate(d *schema.ResourceData, m interface{}) error {
 
 func resourceDashboardJsonUpdate(d *schema.ResourceData, m interface{}) error {
 	log.Printf("[INFO] Update Wavefront Dashboard %s", d.Id())
-	dashboards := m.(*wavefrontClient).client.Dashboards()
-	dashboard, err := buildDashboardJson(d)
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	dashboards := c.client.Dashboards()
+	dashboard, err := buildDashboardJson(d, defaultTagsOf(m))
 
 	if err != nil {
 		return fmt.Errorf("failed to parse dashboard, %s", err)
 	}
 
+	c.cache.evict("dashboard", d.Id())
+	if paths := setStrings(d.Get("ignore_paths")); len(paths) > 0 {
+		dashboard, err = keepIgnoredDashboardPaths(dashboards, d.Id(), dashboard, paths)
+		if err != nil {
+			return err
+		}
+	}
 	err = dashboards.Update(dashboard)
 	if err != nil {
-		return fmt.Errorf("failed to create dashboard, %s", err)
+		return wavefrontError("updating", "Dashboard", d.Id(), err)
 	}
 
 	log.Printf("[INFO] Wavefront Dashboard %s Updated", d.Id())
 	return resourceDashboardJsonRead(d, m)
 }
 
+// keepIgnoredDashboardPaths returns the dashboard with the values at the paths replaced by those of the dashboard
+// with the ID in Wavefront
+func keepIgnoredDashboardPaths(dashboards *wavefront.Dashboards, id string, dashboard *wavefront.Dashboard, paths []string) (*wavefront.Dashboard, error) {
+	server := wavefront.Dashboard{
+		ID: id,
+	}
+	if err := dashboards.Get(&server); err != nil {
+		return nil, wavefrontError("reading", "Dashboard", id, err)
+	}
+	serverJson, err := server.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	configuredJson, err := dashboard.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	kept, err := keepIgnoredPaths(string(configuredJson), string(serverJson), paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to keep the ignore_paths of dashboard %s, %s", id, err)
+	}
+	var merged wavefront.Dashboard
+	if err := merged.UnmarshalJSON([]byte(kept)); err != nil {
+		return nil, err
+	}
+	merged.ID = dashboard.ID
+	return &merged, nil
+}
+
 func resourceDashboardJsonDelete(d *schema.ResourceData, m interface{}) error {
-	dashboards := m.(*wavefrontClient).client.Dashboards()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	dashboards := c.client.Dashboards()
 	dash := wavefront.Dashboard{
 		ID: d.Id(),
 	}
 
-	err := dashboards.Get(&dash)
+	err = dashboards.Get(&dash)
+	if isNotFound(err) {
+		log.Printf("[WARN] Wavefront Dashboard %s already deleted", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("error finding Wavefront Dashboard %s. %s", d.Id(), err)
+		return wavefrontError("reading", "Dashboard", d.Id(), err)
 	}
 
 	// Delete the Dashboard
+	c.cache.evict("dashboard", d.Id())
 	err = dashboards.Delete(&dash)
 	if err != nil {
-		return fmt.Errorf("failed to delete Dashboard %s. %s", d.Id(), err)
+		return wavefrontError("deleting", "Dashboard", d.Id(), err)
 	}
 	d.SetId("")
 	return nil
@@ -151,6 +253,9 @@ func NormalizeDashboardJson(val interface{}) string {
 	dashboard.NumFavorites = 0
 	dashboard.Favorite = false
 
+	// Tags are a set, the order they are given in doesn't matter
+	sort.Strings(dashboard.Tags)
+
 	ret, _ := dashboard.MarshalJSON()
 	return string(ret)
 }
@@ -2,8 +2,8 @@ package wavefront_plugin
 
 import (
 	"fmt"
+	"log"
 	"sort"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/spaceapegames/go-wavefront"
@@ -24,9 +24,10 @@ func resourceDashboard() *schema.Resource {
 					Description: "Name of the Source",
 				},
 				"query": {
-					Type:        schema.TypeString,
-					Required:    true,
-					Description: "Query for the Source",
+					Type:         schema.TypeString,
+					Required:     true,
+					Description:  "Query for the Source",
+					ValidateFunc: validateQuery,
 				},
 				"disabled": {
 					Type:        schema.TypeBool,
@@ -48,6 +49,11 @@ func resourceDashboard() *schema.Resource {
 					Optional:    true,
 					Description: "Description of the source",
 				},
+				"source_color": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "The colour of the source's series, e.g. #80a8e5, overriding the chart's palette",
+				},
 			},
 		},
 	}
@@ -382,6 +388,27 @@ func resourceDashboard() *schema.Resource {
 					Required:    true,
 					Description: "Summarization strategy for the chart. MEAN is default = ['MEAN', 'MEDIAN', 'MIN', 'MAX', 'SUM', 'COUNT', 'LAST', 'FIRST']",
 				},
+				"base": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Default:     1,
+					Description: "The base of the chart's logarithmic Y-axis, 1 for a linear axis",
+				},
+				"include_obsolete_metrics": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Whether to include metrics which have not reported for more than 4 weeks",
+				},
+				"interpolate_points": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Whether to interpolate points which existed in the past or future into the chart's time window",
+				},
+				"no_default_events": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: "Whether to hide the events of the chart's alerts and the dashboard's event query",
+				},
 				"source":        source,
 				"chart_setting": chartSetting,
 			},
@@ -394,6 +421,18 @@ func resourceDashboard() *schema.Resource {
 		Description: "Rows containing chart. Rows belong in Sections",
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Name of the Row",
+				},
+				"height_factor": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      50,
+					Description:  "The height of the Row, 50 by default",
+					ValidateFunc: validateIntAtLeast(1),
+				},
 				"chart": chart,
 			},
 		},
@@ -438,8 +477,9 @@ func resourceDashboard() *schema.Resource {
 					Required: true,
 				},
 				"parameter_type": {
-					Type:     schema.TypeString,
-					Required: true,
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateStringIn(parameterTypes...),
 				},
 				"values_to_readable_strings": {
 					Type:        schema.TypeMap,
@@ -447,16 +487,18 @@ func resourceDashboard() *schema.Resource {
 					Description: "Map of [string]string. At least one of the keys must match the value of default_value.",
 				},
 				"query_value": {
-					Type:     schema.TypeString,
-					Optional: true,
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateQuery,
 				},
 				"tag_key": {
 					Type:     schema.TypeString,
 					Optional: true,
 				},
 				"dynamic_field_type": {
-					Type:     schema.TypeString,
-					Optional: true,
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateStringIn(dynamicFieldTypes...),
 				},
 			},
 		},
@@ -468,10 +510,13 @@ func resourceDashboard() *schema.Resource {
 		Update: resourceDashboardUpdate,
 		Delete: resourceDashboardDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importStateWithCluster,
 		},
+		Timeouts:      resourceTimeouts(),
+		CustomizeDiff: resourceDashboardCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(true),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -494,15 +539,70 @@ func resourceDashboard() *schema.Resource {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
+			// Which events are shown on the dashboard's charts
 			"event_filter_type": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Default:  "BYCHART",
+				ValidateFunc: validateStringIn(
+					"BYCHART", "AUTOMATIC", "ALL", "NONE", "BYDASHBOARD", "BYCHARTANDDASHBOARD"),
+			},
+			// An events() query for the events shown on the dashboard's charts
+			"event_query": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateEventQuery,
+			},
+			"display_description": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			// Whether the dashboard is hidden from the dashboard browser
+			"hidden": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			// The colours of chart titles, e.g. rgba(255,255,255,1), defaulting to Wavefront's theme
+			"chart_title_bg_color": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"chart_title_color": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			// The size of chart titles as a percentage
+			"chart_title_scalar": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIntAtLeast(1),
+			},
+			// The time window the dashboard opens with, e.g. 2h or 7d. Ignored when default_start_time is set
+			"default_time_window": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTimeWindow,
+			},
+			// Epoch milliseconds. The fixed time range the dashboard opens with, ending now if default_end_time is unset
+			"default_start_time": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateIntAtLeast(0),
+			},
+			"default_end_time": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateIntAtLeast(0),
 			},
 			"tags": {
 				Type:     schema.TypeSet,
-				Required: true,
+				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -539,6 +639,8 @@ func buildTerraformSection(wavefrontSection wavefront.Section) map[string]interf
 // Construct a Wavefront Row
 func buildTerraformRow(wavefrontRow wavefront.Row) map[string]interface{} {
 	row := map[string]interface{}{}
+	row["name"] = wavefrontRow.Name
+	row["height_factor"] = wavefrontRow.HeightFactor
 
 	charts := []map[string]interface{}{}
 	for _, wavefrontRow := range wavefrontRow.Charts {
@@ -562,6 +664,10 @@ func buildTerraformChart(wavefrontChart wavefront.Chart) map[string]interface{}
 	}
 	chart["source"] = sources
 	chart["summarization"] = wavefrontChart.Summarization
+	chart["base"] = wavefrontChart.Base
+	chart["include_obsolete_metrics"] = wavefrontChart.IncludeObsoleteMetrics
+	chart["interpolate_points"] = wavefrontChart.InterpolatePoints
+	chart["no_default_events"] = wavefrontChart.NoDefaultEvents
 	chart["chart_setting"] = []interface{}{buildTerraformChartSettings(wavefrontChart.ChartSettings)}
 	return chart
 }
@@ -623,6 +729,8 @@ func buildTerraformChartSettings(wavefrontChartSettings wavefront.ChartSetting)
 	chartSettings["y1_units"] = wavefrontChartSettings.Y1Units
 	chartSettings["y1max"] = wavefrontChartSettings.Y1Max
 	chartSettings["y1min"] = wavefrontChartSettings.Y1Min
+	chartSettings["ymax"] = wavefrontChartSettings.Ymax
+	chartSettings["ymin"] = wavefrontChartSettings.Ymin
 	return chartSettings
 }
 
@@ -635,6 +743,7 @@ func buildTerraformSource(wavefrontSource wavefront.Source) map[string]interface
 	source["scatter_plot_source"] = wavefrontSource.ScatterPlotSource
 	source["query_builder_enabled"] = wavefrontSource.QuerybuilderEnabled
 	source["source_description"] = wavefrontSource.SourceDescription
+	source["source_color"] = wavefrontSource.SourceColor
 
 	return source
 }
@@ -668,6 +777,12 @@ func buildRows(terraformRows *[]interface{}) *[]wavefront.Row {
 		wavefrontRows[i] = wavefront.Row{
 			Charts: *buildCharts(&terraformCharts),
 		}
+		if t["name"] != nil {
+			wavefrontRows[i].Name = t["name"].(string)
+		}
+		if t["height_factor"] != nil {
+			wavefrontRows[i].HeightFactor = t["height_factor"].(int)
+		}
 	}
 
 	return &wavefrontRows
@@ -691,6 +806,18 @@ func buildCharts(terraformCharts *[]interface{}) *[]wavefront.Chart {
 			Summarization: t["summarization"].(string),
 			ChartSettings: *buildChartSettings(&terraformChartSettings),
 		}
+		if t["base"] != nil {
+			wavefrontCharts[i].Base = t["base"].(int)
+		}
+		if t["include_obsolete_metrics"] != nil {
+			wavefrontCharts[i].IncludeObsoleteMetrics = t["include_obsolete_metrics"].(bool)
+		}
+		if t["interpolate_points"] != nil {
+			wavefrontCharts[i].InterpolatePoints = t["interpolate_points"].(bool)
+		}
+		if t["no_default_events"] != nil {
+			wavefrontCharts[i].NoDefaultEvents = t["no_default_events"].(bool)
+		}
 	}
 
 	return &wavefrontCharts
@@ -916,6 +1043,9 @@ func buildSources(terraformSources *[]interface{}) *[]wavefront.Source {
 		if t["source_description"] != nil {
 			wavefrontSources[i].SourceDescription = t["source_description"].(string)
 		}
+		if t["source_color"] != nil {
+			wavefrontSources[i].SourceColor = t["source_color"].(string)
+		}
 	}
 
 	return &wavefrontSources
@@ -958,13 +1088,10 @@ func buildParameterDetails(terraformParams *[]interface{}) *map[string]wavefront
 	return &wavefrontParams
 }
 
-// Construct a Wavefront Dashboard
-func buildDashboard(d *schema.ResourceData) (*wavefront.Dashboard, error) {
+// Construct a Wavefront Dashboard, its tags including the provider's default tags
+func buildDashboard(d *schema.ResourceData, defaultTags []string) (*wavefront.Dashboard, error) {
 
-	var tags []string
-	for _, tag := range d.Get("tags").(*schema.Set).List() {
-		tags = append(tags, tag.(string))
-	}
+	tags := mergeTags(setStrings(d.Get("tags")), defaultTags)
 
 	terraformSections := d.Get("section").([]interface{})
 	terraformParams := d.Get("parameter_details").([]interface{})
@@ -994,21 +1121,36 @@ func buildDashboard(d *schema.ResourceData) (*wavefront.Dashboard, error) {
 		EventFilterType:               eventFilterType,
 		DisplaySectionTableOfContents: displayTOC,
 		DisplayQueryParameters:        displayQP,
+		EventQuery:                    d.Get("event_query").(string),
+		DisplayDescription:            d.Get("display_description").(bool),
+		Hidden:                        d.Get("hidden").(bool),
+		ChartTitleBgColor:             d.Get("chart_title_bg_color").(string),
+		ChartTitleColor:               d.Get("chart_title_color").(string),
+		ChartTitleScalar:              d.Get("chart_title_scalar").(int),
+		DefaultTimeWindow:             d.Get("default_time_window").(string),
+		DefaultStartTime:              d.Get("default_start_time").(int),
+		DefaultEndTime:                d.Get("default_end_time").(int),
 	}, nil
 }
 
 // Create a Terraform Dashboard
 func resourceDashboardCreate(d *schema.ResourceData, m interface{}) error {
-	dashboards := m.(*wavefrontClient).client.Dashboards()
-	dashboard, err := buildDashboard(d)
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	dashboards := c.client.Dashboards()
+	dashboard, err := buildDashboard(d, defaultTagsOf(m))
 
 	if err != nil {
 		return fmt.Errorf("failed to parse dashboard, %s", err)
 	}
 
+	c.cache.evict("dashboard", dashboard.ID)
 	err = dashboards.Create(dashboard)
 	if err != nil {
-		return fmt.Errorf("failed to create dashboard, %s", err)
+		return wavefrontError("creating", "Dashboard", d.Get("url"), err)
 	}
 	d.SetId(dashboard.ID)
 
@@ -1025,19 +1167,27 @@ func (p Params) Less(i, j int) bool {
 
 // Read a Wavefront Dashboard
 func resourceDashboardRead(d *schema.ResourceData, m interface{}) error {
-	dashboards := m.(*wavefrontClient).client.Dashboards()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	dashboards := c.client.Dashboards()
 	dash := wavefront.Dashboard{
 		ID: d.Id(),
 	}
 
 	// search for an dashboard with our id. We should receive 1 (Exact Match) or 0 (No Match)
-	err := dashboards.Get(&dash)
+	err = c.cache.read(&c.client, "dashboard", dash.ID, &dash, func() error {
+		return dashboards.Get(&dash)
+	})
+	if isNotFound(err) {
+		log.Printf("[WARN] Wavefront Dashboard %s not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			d.SetId("")
-		} else {
-			return fmt.Errorf("error finding Wavefront Dashboard %s. %s", d.Id(), err)
-		}
+		return wavefrontError("reading", "Dashboard", d.Id(), err)
 	}
 
 	// Use the Wavefront url as the Terraform ID
@@ -1048,6 +1198,16 @@ func resourceDashboardRead(d *schema.ResourceData, m interface{}) error {
 
 	d.Set("display_section_table_of_contents", dash.DisplaySectionTableOfContents)
 	d.Set("display_query_parameters", dash.DisplayQueryParameters)
+	d.Set("event_filter_type", dash.EventFilterType)
+	d.Set("event_query", dash.EventQuery)
+	d.Set("display_description", dash.DisplayDescription)
+	d.Set("hidden", dash.Hidden)
+	d.Set("chart_title_bg_color", dash.ChartTitleBgColor)
+	d.Set("chart_title_color", dash.ChartTitleColor)
+	d.Set("chart_title_scalar", dash.ChartTitleScalar)
+	d.Set("default_time_window", dash.DefaultTimeWindow)
+	d.Set("default_start_time", dash.DefaultStartTime)
+	d.Set("default_end_time", dash.DefaultEndTime)
 
 	sections := []map[string]interface{}{}
 	for _, wavefrontSection := range dash.Sections {
@@ -1064,42 +1224,60 @@ func resourceDashboardRead(d *schema.ResourceData, m interface{}) error {
 	sort.Sort(Params(parameterDetails))
 
 	d.Set("parameter_details", parameterDetails)
-	d.Set("tags", dash.Tags)
+	d.Set("tags", withoutDefaultTags(dash.Tags, setStrings(d.Get("tags")), defaultTagsOf(m)))
+	d.Set("tags_all", dash.Tags)
 
 	return nil
 }
 
 func resourceDashboardUpdate(d *schema.ResourceData, m interface{}) error {
-	dashboards := m.(*wavefrontClient).client.Dashboards()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	dashboards := c.client.Dashboards()
 
-	a, err := buildDashboard(d)
+	a, err := buildDashboard(d, defaultTagsOf(m))
 	if err != nil {
 		return fmt.Errorf("failed to parse dashboard, %s", err)
 	}
 
+	c.cache.evict("dashboard", d.Id())
 	// Update the dashboard on Wavefront
 	err = dashboards.Update(a)
 	if err != nil {
-		return fmt.Errorf("error Updating Dashboard %s. %s", d.Get("name"), err)
+		return wavefrontError("updating", "Dashboard", d.Id(), err)
 	}
 	return resourceDashboardRead(d, m)
 }
 
 func resourceDashboardDelete(d *schema.ResourceData, m interface{}) error {
-	dashboards := m.(*wavefrontClient).client.Dashboards()
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	dashboards := c.client.Dashboards()
 	dash := wavefront.Dashboard{
 		ID: d.Id(),
 	}
 
-	err := dashboards.Get(&dash)
+	err = dashboards.Get(&dash)
+	if isNotFound(err) {
+		log.Printf("[WARN] Wavefront Dashboard %s already deleted", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("error finding Wavefront Dashboard %s. %s", d.Id(), err)
+		return wavefrontError("reading", "Dashboard", d.Id(), err)
 	}
 
 	// Delete the Dashboard
+	c.cache.evict("dashboard", d.Id())
 	err = dashboards.Delete(&dash)
 	if err != nil {
-		return fmt.Errorf("failed to delete Dashboard %s. %s", d.Id(), err)
+		return wavefrontError("deleting", "Dashboard", d.Id(), err)
 	}
 	d.SetId("")
 	return nil
@@ -0,0 +1,133 @@
+package wavefront_plugin
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// The values Wavefront gives the attributes of each kind of object in a dashboard when they are omitted. Attributes
+// missing from here default to their zero value.
+var dashboardJsonDefaults = map[string]map[string]interface{}{
+	"dashboard": {
+		"eventFilterType": "BYCHART",
+	},
+	"row": {
+		"heightFactor": float64(50),
+	},
+	"chart": {
+		"base":          float64(1),
+		"summarization": "MEAN",
+	},
+	"chartSettings": {
+		"type":                   "line",
+		"lineType":               "linear",
+		"stackType":              "zero",
+		"windowing":              "full",
+		"tagMode":                "all",
+		"fixedLegendFilterField": "CURRENT",
+		"fixedLegendFilterSort":  "TOP",
+		"fixedLegendPosition":    "RIGHT",
+	},
+	"source": {
+		"scatterPlotSource": "Y",
+	},
+}
+
+// The kind of the objects held by the attributes of each kind of object in a dashboard, for looking up their defaults
+var dashboardJsonKinds = map[string]map[string]string{
+	"dashboard": {"sections": "section"},
+	"section":   {"rows": "row"},
+	"row":       {"charts": "chart"},
+	"chart":     {"chartSettings": "chartSettings", "sources": "source"},
+}
+
+// suppressEquivalentDashboardJson is a DiffSuppressFunc which ignores the differences between two dashboard_json
+// values which Wavefront treats as the same dashboard: the order of their keys and tags, the metadata Wavefront
+// adds, attributes which are omitted from one and set to Wavefront's default in the other, and the resource's
+// ignore_paths
+func suppressEquivalentDashboardJson(k, old, new string, d *schema.ResourceData) bool {
+	if old != "" && new != "" {
+		kept, err := keepIgnoredPaths(new, old, setStrings(d.Get("ignore_paths")))
+		if err != nil {
+			return false
+		}
+		new = kept
+	}
+	return equivalentDashboardJson(old, new)
+}
+
+// equivalentDashboardJson reports whether two dashboard JSON documents describe the same dashboard
+func equivalentDashboardJson(a string, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	ca, err := canonicalDashboardJson(a)
+	if err != nil {
+		return false
+	}
+	cb, err := canonicalDashboardJson(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(ca, cb)
+}
+
+// canonicalDashboardJson decodes a normalized dashboard with Wavefront's defaults applied and its zero values removed
+func canonicalDashboardJson(s string) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(NormalizeDashboardJson(s)), &v); err != nil {
+		return nil, err
+	}
+	return canonicalDashboardValue(v, "dashboard"), nil
+}
+
+// canonicalDashboardValue applies the defaults of the kind of object v is to it and its children, then removes the
+// attributes left with their zero value, so that an omitted attribute and one set to its default compare equal
+func canonicalDashboardValue(v interface{}, kind string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		obj := map[string]interface{}{}
+		for key, value := range v {
+			obj[key] = canonicalDashboardValue(value, dashboardJsonKinds[kind][key])
+		}
+		for key, value := range dashboardJsonDefaults[kind] {
+			if isZeroJson(obj[key]) {
+				obj[key] = value
+			}
+		}
+		for key, value := range obj {
+			if isZeroJson(value) {
+				delete(obj, key)
+			}
+		}
+		return obj
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = canonicalDashboardValue(item, kind)
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+func isZeroJson(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
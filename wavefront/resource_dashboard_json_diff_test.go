@@ -0,0 +1,160 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestEquivalentDashboardJson(t *testing.T) {
+	configured := `{
+  "url": "tftest",
+  "name": "Test",
+  "sections": [{"name": "section 1", "rows": [{"charts": [{
+    "name": "chart 1",
+    "sources": [{"query": "ts()", "name": "source 1"}],
+    "chartSettings": {"type": "line"}
+  }]}]}],
+  "tags": {"customerTags": ["b", "a"]}
+}`
+	// As returned by Wavefront, with its defaults and metadata filled in and the keys in another order
+	server := `{
+  "id": "tftest",
+  "name": "Test",
+  "url": "tftest",
+  "creatorId": "someone@example.com",
+  "updatedEpochMillis": 1500000000000,
+  "eventFilterType": "BYCHART",
+  "parameters": {},
+  "tags": {"customerTags": ["a", "b"]},
+  "sections": [{"name": "section 1", "rows": [{"heightFactor": 50, "name": "", "charts": [{
+    "base": 1,
+    "chartSettings": {"lineType": "linear", "stackType": "zero", "type": "line", "windowing": "full"},
+    "includeObsoleteMetrics": false,
+    "name": "chart 1",
+    "sources": [{"name": "source 1", "query": "ts()", "scatterPlotSource": "Y", "sourceColor": "", "querybuilderEnabled": false}],
+    "summarization": "MEAN"
+  }]}]}]
+}`
+	if !equivalentDashboardJson(configured, server) {
+		t.Errorf("expected the configured dashboard to be equivalent to Wavefront's")
+	}
+
+	for _, change := range []struct{ from, to string }{
+		{`"name": "chart 1"`, `"name": "chart 2"`},
+		{`"chartSettings": {"type": "line"}`, `"chartSettings": {"type": "table"}`},
+		{`"chartSettings": {"type": "line"}`, `"chartSettings": {"type": "line", "stackType": "expand"}`},
+		{`"chartSettings": {"type": "line"}`, `"chartSettings": {"type": "line"}, "base": 2`},
+		{`"rows": [{"charts"`, `"rows": [{"heightFactor": 100, "charts"`},
+		{`{"query": "ts()", "name": "source 1"}`, `{"query": "ts()", "name": "source 1", "sourceColor": "#80a8e5"}`},
+		{`{"query": "ts()", "name": "source 1"}`, `{"query": "ts()", "name": "source 1", "scatterPlotSource": "X"}`},
+		{`"customerTags": ["b", "a"]`, `"customerTags": ["b"]`},
+	} {
+		changed := replaceOnce(t, configured, change.from, change.to)
+		if equivalentDashboardJson(changed, server) {
+			t.Errorf("expected %s to be a change from Wavefront's dashboard", change.to)
+		}
+	}
+
+	if equivalentDashboardJson("", server) {
+		t.Errorf("expected a new dashboard not to be equivalent to Wavefront's")
+	}
+}
+
+// Two charts swapping places in a row is a change
+func TestEquivalentDashboardJson_order(t *testing.T) {
+	a := `{"url": "tftest", "sections": [{"rows": [{"charts": [{"name": "a"}, {"name": "b"}]}]}]}`
+	b := `{"url": "tftest", "sections": [{"rows": [{"charts": [{"name": "b"}, {"name": "a"}]}]}]}`
+	if equivalentDashboardJson(a, b) {
+		t.Errorf("expected the order of charts to matter")
+	}
+}
+
+func replaceOnce(t *testing.T, s, from, to string) string {
+	if !strings.Contains(s, from) {
+		t.Fatalf("%s not found", from)
+	}
+	return strings.Replace(s, from, to, 1)
+}
+
+// Wavefront's defaults for the attributes the dashboard_json omits don't show as a diff, while changes still do
+func TestAccWavefrontDashboardJson_ServerDefaults(t *testing.T) {
+	var record wavefront.Dashboard
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardJsonDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontDashboardJson_defaults(""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontDashboardJsonExists("wavefront_dashboard_json.defaults", &record),
+					testAccCheckWavefrontDashboardJsonChart(&record, 1, 50),
+				),
+			},
+			{
+				Config:   testAccCheckWavefrontDashboardJson_defaults(`"heightFactor": 50,`),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCheckWavefrontDashboardJson_defaults(`"heightFactor": 100,`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontDashboardJsonExists("wavefront_dashboard_json.defaults", &record),
+					testAccCheckWavefrontDashboardJsonChart(&record, 1, 100),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontDashboardJsonChart(dashboard *wavefront.Dashboard, base int, heightFactor int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		row := dashboard.Sections[0].Rows[0]
+		if row.HeightFactor != heightFactor {
+			return fmt.Errorf("Bad height factor: expected %d, got %d", heightFactor, row.HeightFactor)
+		}
+		if chart := row.Charts[0]; chart.Base != base {
+			return fmt.Errorf("Bad base: expected %d, got %d", base, chart.Base)
+		}
+		return nil
+	}
+}
+
+func testAccCheckWavefrontDashboardJson_defaults(row string) string {
+	return fmt.Sprintf(`
+resource "wavefront_dashboard_json" "defaults" {
+  dashboard_json = <<EOF
+{
+  "url": "tftestdefaults",
+  "name": "Terraform Test Dashboard Json Defaults",
+  "sections": [
+    {
+      "name": "section 1",
+      "rows": [
+        {
+          %s
+          "charts": [
+            {
+              "sources": [
+                {
+                  "query": "ts()",
+                  "name": "source 1"
+                }
+              ],
+              "name": "chart 1"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+EOF
+}
+`, row)
+}
@@ -0,0 +1,157 @@
+package wavefront_plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is one step of an ignore_paths expression: an object key, any key (*), a list index or any index ([*])
+type jsonPathStep struct {
+	key   string
+	index int
+	list  bool
+	any   bool
+}
+
+// parseJsonPath parses a JSONPath-like expression such as parameterDetails.*.defaultValue or
+// sections[*].rows[0].charts[*].chartSettings.ymax, with an optional leading $.
+func parseJsonPath(path string) ([]jsonPathStep, error) {
+	p := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if strings.TrimSpace(p) == "" {
+		return nil, fmt.Errorf("path %q is empty", path)
+	}
+
+	var steps []jsonPathStep
+	for _, segment := range strings.Split(p, ".") {
+		name := segment
+		if i := strings.Index(segment, "["); i >= 0 {
+			name = segment[:i]
+		}
+		if strings.ContainsAny(name, "]") {
+			return nil, fmt.Errorf("path %q has an unexpected ] in %q", path, segment)
+		}
+		if name != "" {
+			steps = append(steps, jsonPathStep{key: name, any: name == "*"})
+		} else if len(steps) == 0 || segment == "" {
+			return nil, fmt.Errorf("path %q has an empty key in %q", path, segment)
+		}
+
+		rest := segment[len(name):]
+		for rest != "" {
+			end := strings.Index(rest, "]")
+			if rest[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("path %q has an unclosed [ in %q", path, segment)
+			}
+			index := rest[1:end]
+			if index == "*" {
+				steps = append(steps, jsonPathStep{list: true, any: true})
+			} else if n, err := strconv.Atoi(index); err == nil && n >= 0 {
+				steps = append(steps, jsonPathStep{list: true, index: n})
+			} else {
+				return nil, fmt.Errorf("path %q has an invalid index [%s], expected a number or *", path, index)
+			}
+			rest = rest[end+1:]
+		}
+	}
+	return steps, nil
+}
+
+// validateJsonPath is a ValidateFunc which checks that a string attribute is an ignore_paths expression
+func validateJsonPath(val interface{}, key string) ([]string, []error) {
+	if _, err := parseJsonPath(val.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", key, err)}
+	}
+	return nil, nil
+}
+
+// keepIgnoredPaths returns the configured dashboard JSON with the values at each of the paths replaced by the server's
+// values, so that a dashboard sent to Wavefront keeps what was changed there and a diff ignores those paths
+func keepIgnoredPaths(configured string, server string, paths []string) (string, error) {
+	if len(paths) == 0 {
+		return configured, nil
+	}
+
+	var dst, src interface{}
+	if err := json.Unmarshal([]byte(configured), &dst); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal([]byte(server), &src); err != nil {
+		return "", err
+	}
+	for _, path := range paths {
+		steps, err := parseJsonPath(path)
+		if err != nil {
+			return "", err
+		}
+		dst, _ = copyJsonPath(dst, src, steps)
+	}
+
+	bytes, err := json.Marshal(dst)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// copyJsonPath copies the values matching the path from src into dst, returning dst and whether it still has a value.
+// The server's value replaces the configured one, and is removed where the server has none. Where the path passes
+// through an object or list element which only one of them has, nothing is copied.
+func copyJsonPath(dst interface{}, src interface{}, steps []jsonPathStep) (interface{}, bool) {
+	if len(steps) == 0 {
+		return src, src != nil
+	}
+	step, rest := steps[0], steps[1:]
+
+	if step.list {
+		d, dok := dst.([]interface{})
+		s, sok := src.([]interface{})
+		if !dok || !sok {
+			return dst, dst != nil
+		}
+		if step.any && len(rest) == 0 {
+			// Every element is ignored, so the server's list is kept whole
+			return s, true
+		}
+		for i := range d {
+			if i < len(s) && (step.any || i == step.index) {
+				// A list element keeps its place, even once its value is removed
+				d[i], _ = copyJsonPath(d[i], s[i], rest)
+			}
+		}
+		return d, true
+	}
+
+	d, dok := dst.(map[string]interface{})
+	s, sok := src.(map[string]interface{})
+	if !dok || !sok {
+		return dst, dst != nil
+	}
+	keys := []string{step.key}
+	if step.any {
+		keys = nil
+		for k := range d {
+			keys = append(keys, k)
+		}
+		// Every key is ignored, so the keys only the server has are kept too
+		if len(rest) == 0 {
+			for k := range s {
+				if _, ok := d[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+		}
+	}
+	for _, k := range keys {
+		if _, ok := d[k]; !ok && len(rest) > 0 {
+			continue
+		}
+		if v, ok := copyJsonPath(d[k], s[k], rest); ok {
+			d[k] = v
+		} else {
+			delete(d, k)
+		}
+	}
+	return d, true
+}
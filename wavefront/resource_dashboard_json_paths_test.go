@@ -0,0 +1,271 @@
+package wavefront_plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestParseJsonPath(t *testing.T) {
+	valid := map[string][]jsonPathStep{
+		"name":                            {{key: "name"}},
+		"$.name":                          {{key: "name"}},
+		"parameterDetails.*.defaultValue": {{key: "parameterDetails"}, {key: "*", any: true}, {key: "defaultValue"}},
+		"sections[*].rows[0].charts[*].chartSettings.ymax": {
+			{key: "sections"}, {list: true, any: true},
+			{key: "rows"}, {list: true, index: 0},
+			{key: "charts"}, {list: true, any: true},
+			{key: "chartSettings"}, {key: "ymax"},
+		},
+		"sections[1][2]": {{key: "sections"}, {list: true, index: 1}, {list: true, index: 2}},
+	}
+	for path, expected := range valid {
+		steps, err := parseJsonPath(path)
+		if err != nil {
+			t.Errorf("expected %s to be valid, got %s", path, err)
+			continue
+		}
+		if !reflect.DeepEqual(steps, expected) {
+			t.Errorf("%s: expected %+v, got %+v", path, expected, steps)
+		}
+	}
+
+	for _, path := range []string{"", "$", "sections..name", "sections[", "sections[x]", "sections[-1]", "[0]", "sections]", "sections[0]x"} {
+		if _, err := parseJsonPath(path); err == nil {
+			t.Errorf("expected %q to be invalid", path)
+		}
+	}
+}
+
+func TestKeepIgnoredPaths(t *testing.T) {
+	configured := `{
+  "name": "configured",
+  "parameterDetails": {
+    "env": {"defaultValue": "prod", "label": "Environment"},
+    "region": {"defaultValue": "eu", "label": "Region"}
+  },
+  "sections": [{"rows": [{"charts": [
+    {"name": "a", "chartSettings": {"type": "line", "ymax": 100}},
+    {"name": "b", "chartSettings": {"type": "line"}}
+  ]}]}]
+}`
+	server := `{
+  "name": "server",
+  "parameterDetails": {
+    "env": {"defaultValue": "dev", "label": "Env"},
+    "zone": {"defaultValue": "a", "label": "Zone"}
+  },
+  "sections": [{"rows": [{"charts": [
+    {"name": "a", "chartSettings": {"type": "line"}},
+    {"name": "b", "chartSettings": {"type": "line", "ymax": 50}},
+    {"name": "c", "chartSettings": {"type": "line", "ymax": 10}}
+  ]}]}]
+}`
+
+	cases := []struct {
+		paths    []string
+		expected string
+	}{
+		{nil, configured},
+		{[]string{"name"}, `"name": "server"`},
+		{[]string{"$.name"}, `"name": "server"`},
+		// Only the parameters which are configured are changed
+		{[]string{"parameterDetails.*.defaultValue"}, `"parameterDetails": {
+    "env": {"defaultValue": "dev", "label": "Environment"},
+    "region": {"defaultValue": "eu", "label": "Region"}
+  }`},
+		{[]string{"parameterDetails.*"}, `"parameterDetails": {
+    "env": {"defaultValue": "dev", "label": "Env"},
+    "zone": {"defaultValue": "a", "label": "Zone"}
+  }`},
+		// The configured ymax is removed where the server has none, and the charts only the server has are left out
+		{[]string{"sections[*].rows[*].charts[*].chartSettings.ymax"}, `"sections": [{"rows": [{"charts": [
+    {"name": "a", "chartSettings": {"type": "line"}},
+    {"name": "b", "chartSettings": {"type": "line", "ymax": 50}}
+  ]}]}]`},
+		{[]string{"sections[0].rows[0].charts[1]"}, `"sections": [{"rows": [{"charts": [
+    {"name": "a", "chartSettings": {"type": "line", "ymax": 100}},
+    {"name": "b", "chartSettings": {"type": "line", "ymax": 50}}
+  ]}]}]`},
+		{[]string{"sections[0].rows[*].charts[*]"}, `"sections": [{"rows": [{"charts": [
+    {"name": "a", "chartSettings": {"type": "line"}},
+    {"name": "b", "chartSettings": {"type": "line", "ymax": 50}},
+    {"name": "c", "chartSettings": {"type": "line", "ymax": 10}}
+  ]}]}]`},
+		// Paths which match nothing change nothing
+		{[]string{"sections[5].name", "missing.key", "name[0]"}, configured},
+	}
+	for _, c := range cases {
+		kept, err := keepIgnoredPaths(configured, server, c.paths)
+		if err != nil {
+			t.Errorf("%v: %s", c.paths, err)
+			continue
+		}
+		expected := mergeJson(t, configured, c.expected)
+		var actual interface{}
+		if err := json.Unmarshal([]byte(kept), &actual); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%v: expected %v, got %v", c.paths, expected, actual)
+		}
+	}
+
+	if _, err := keepIgnoredPaths(configured, server, []string{"sections[x]"}); err == nil {
+		t.Errorf("expected an invalid path to be an error")
+	}
+}
+
+// mergeJson decodes the JSON object with the top level attributes in change replaced
+func mergeJson(t *testing.T, object string, change string) interface{} {
+	var obj, changed map[string]interface{}
+	if err := json.Unmarshal([]byte(object), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if change != object {
+		if err := json.Unmarshal([]byte("{"+change+"}"), &changed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for k, v := range changed {
+		obj[k] = v
+	}
+	return obj
+}
+
+// The paths edited in the UI are left out of plans, and keep their values when the dashboard is updated
+func TestAccWavefrontDashboardJson_IgnorePaths(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("edits in the Wavefront UI are simulated by the mock Wavefront API")
+	}
+	var record wavefront.Dashboard
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardJsonDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontDashboardJson_ignorePaths("Terraform Test Dashboard Json Ignore Paths"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontDashboardJsonExists("wavefront_dashboard_json.ignore_paths", &record),
+					testAccCheckWavefrontDashboardJsonDefaultValue(&record, "Terraform Test Dashboard Json Ignore Paths", "prod", 100),
+					resource.TestCheckResourceAttr("wavefront_dashboard_json.ignore_paths", "ignore_paths.#", "2"),
+				),
+			},
+			{
+				PreConfig: func() {
+					testAccMock.edit("dashboard", "tftestignorepaths", func(obj map[string]interface{}) {
+						param := obj["parameterDetails"].(map[string]interface{})["env"].(map[string]interface{})
+						param["defaultValue"] = "dev"
+						row := obj["sections"].([]interface{})[0].(map[string]interface{})["rows"].([]interface{})[0]
+						chart := row.(map[string]interface{})["charts"].([]interface{})[0].(map[string]interface{})
+						chart["chartSettings"].(map[string]interface{})["ymax"] = 50
+					})
+				},
+				Config:   testAccCheckWavefrontDashboardJson_ignorePaths("Terraform Test Dashboard Json Ignore Paths"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCheckWavefrontDashboardJson_ignorePaths("Terraform Test Dashboard Json Ignore Paths Updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontDashboardJsonExists("wavefront_dashboard_json.ignore_paths", &record),
+					testAccCheckWavefrontDashboardJsonDefaultValue(&record, "Terraform Test Dashboard Json Ignore Paths Updated", "dev", 50),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontDashboardJson_InvalidIgnorePaths(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardJsonDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "wavefront_dashboard_json" "ignore_paths" {
+  dashboard_json = "{\"url\": \"tftestignorepaths\", \"name\": \"Terraform Test\"}"
+  ignore_paths = ["sections[x].name"]
+}
+`,
+				ExpectError: regexp.MustCompile(`path "sections\[x\].name" has an invalid index \[x\], expected a number or \*`),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontDashboardJsonDefaultValue(dashboard *wavefront.Dashboard, name string, defaultValue string, ymax float32) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if dashboard.Name != name {
+			return fmt.Errorf("Bad name: expected %s, got %s", name, dashboard.Name)
+		}
+		if v := dashboard.ParameterDetails["env"].DefaultValue; v != defaultValue {
+			return fmt.Errorf("Bad env defaultValue: expected %s, got %s", defaultValue, v)
+		}
+		if v := dashboard.Sections[0].Rows[0].Charts[0].ChartSettings.Ymax; v != ymax {
+			return fmt.Errorf("Bad ymax: expected %g, got %g", ymax, v)
+		}
+		return nil
+	}
+}
+
+func testAccCheckWavefrontDashboardJson_ignorePaths(name string) string {
+	return fmt.Sprintf(`
+resource "wavefront_dashboard_json" "ignore_paths" {
+  ignore_paths = [
+    "parameterDetails.*.defaultValue",
+    "sections[*].rows[*].charts[*].chartSettings.ymax",
+  ]
+  dashboard_json = <<EOF
+{
+  "url": "tftestignorepaths",
+  "name": "%s",
+  "sections": [
+    {
+      "name": "section 1",
+      "rows": [
+        {
+          "charts": [
+            {
+              "name": "chart 1",
+              "sources": [
+                {
+                  "name": "source 1",
+                  "query": "ts(cpu.load, env=$${env})"
+                }
+              ],
+              "chartSettings": {
+                "type": "line",
+                "ymax": 100
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ],
+  "parameterDetails": {
+    "env": {
+      "label": "Environment",
+      "defaultValue": "prod",
+      "hideFromView": false,
+      "parameterType": "LIST",
+      "valuesToReadableStrings": {
+        "dev": "dev",
+        "prod": "prod"
+      }
+    }
+  }
+}
+EOF
+}
+`, name)
+}
@@ -11,7 +11,7 @@ import (
 func TestAccWavefrontDashboardJson_Basic(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardJsonDestroy,
@@ -33,7 +33,7 @@ func TestAccWavefrontDashboardJson_Basic(t *testing.T) {
 func TestAccWavefrontDashboardJson_Updated(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardJsonDestroy,
@@ -63,7 +63,7 @@ func TestAccWavefrontDashboardJson_Updated(t *testing.T) {
 func TestAccWavefrontDashboardJson_Multiple(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardJsonDestroy,
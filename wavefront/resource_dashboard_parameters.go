@@ -0,0 +1,157 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/terraform-provider-wavefront/wql"
+)
+
+var (
+	parameterTypes    = []string{"SIMPLE", "LIST", "DYNAMIC"}
+	dynamicFieldTypes = []string{"SOURCE", "SOURCE_TAG", "METRIC_NAME", "TAG_KEY", "MATCHING_SOURCE_TAG"}
+)
+
+// Check the dashboard's parameter_details, the parameters referenced by its sources and its default time range at
+// plan time, and plan its tags_all
+func resourceDashboardCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("default_start_time") && d.NewValueKnown("default_end_time") {
+		start, end := d.Get("default_start_time").(int), d.Get("default_end_time").(int)
+		if end != 0 && end <= start {
+			return fmt.Errorf("default_end_time of dashboard %s must be after its default_start_time", d.Get("url"))
+		}
+	}
+	errs, warnings := validateDashboardParameters(
+		d.Get("section").([]interface{}),
+		d.Get("parameter_details").([]interface{}),
+		d.NewValueKnown,
+	)
+	for _, w := range warnings {
+		log.Printf("[WARN] dashboard %s: %s", d.Get("url"), w)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid parameters for dashboard %s: %s", d.Get("url"), strings.Join(errs, ", "))
+	}
+	return customizeDiffTagsAll(d, setStrings(d.Get("tags")), d.NewValueKnown("tags"), m)
+}
+
+// validateDashboardParameters returns the problems found with a dashboard's parameters, as errors and warnings.
+// known reports whether the value of an attribute is known, values which are not yet known are not checked.
+func validateDashboardParameters(sections []interface{}, params []interface{}, known func(string) bool) ([]string, []string) {
+	var errs, warnings []string
+
+	defined := map[string]bool{}
+	for i, p := range params {
+		param := p.(map[string]interface{})
+		key := fmt.Sprintf("parameter_details.%d", i)
+		name := param["name"].(string)
+		if !known(key + ".name") {
+			continue
+		}
+		if defined[name] {
+			errs = append(errs, fmt.Sprintf("%s.name: parameter %q is defined more than once", key, name))
+		}
+		defined[name] = true
+		errs = append(errs, validateParameterDetail(key, param, known)...)
+	}
+
+	used := map[string]bool{}
+	forEachSource(sections, func(key string, source map[string]interface{}, siblings map[string]bool) {
+		if !known(key + ".query") {
+			return
+		}
+		// Invalid queries are reported by the query's ValidateFunc
+		q, err := wql.Parse(source["query"].(string))
+		if err != nil {
+			return
+		}
+		for _, name := range q.Params {
+			used[name] = true
+			// ${name} may also refer to another source of the same chart, e.g. ${A} / ${B}
+			if !defined[name] && !siblings[name] {
+				errs = append(errs, fmt.Sprintf("%s.query references undefined parameter %q", key, name))
+			}
+		}
+	})
+
+	var unused []string
+	for name := range defined {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		warnings = append(warnings, fmt.Sprintf("parameter %q is not used by any source", name))
+	}
+
+	return errs, warnings
+}
+
+// Check the fields of a single parameter_details block against its parameter_type
+func validateParameterDetail(key string, param map[string]interface{}, known func(string) bool) []string {
+	var errs []string
+
+	values := param["values_to_readable_strings"].(map[string]interface{})
+	defaultValue := param["default_value"].(string)
+	if known(key+".values_to_readable_strings") && known(key+".default_value") {
+		if _, ok := values[defaultValue]; !ok {
+			keys := []string{}
+			for k := range values {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			errs = append(errs, fmt.Sprintf("%s.default_value %q must be one of the keys of values_to_readable_strings [%s]",
+				key, defaultValue, strings.Join(keys, ", ")))
+		}
+	}
+
+	set := func(field string) bool {
+		v, _ := param[field].(string)
+		return v != "" || !known(key+"."+field)
+	}
+
+	switch param["parameter_type"].(string) {
+	case "SIMPLE", "LIST":
+		for _, field := range []string{"query_value", "dynamic_field_type", "tag_key"} {
+			if set(field) {
+				errs = append(errs, fmt.Sprintf("%s.%s cannot be set for %s parameters", key, field, param["parameter_type"]))
+			}
+		}
+	case "DYNAMIC":
+		for _, field := range []string{"query_value", "dynamic_field_type"} {
+			if !set(field) {
+				errs = append(errs, fmt.Sprintf("%s.%s must be supplied for DYNAMIC parameters", key, field))
+			}
+		}
+		if param["dynamic_field_type"] == "TAG_KEY" && !set("tag_key") {
+			errs = append(errs, fmt.Sprintf("%s.tag_key must be supplied for TAG_KEY parameters", key))
+		}
+	}
+
+	return errs
+}
+
+// forEachSource calls f with every source of the dashboard's sections, the attribute path to it and the names of
+// the sources of its chart
+func forEachSource(sections []interface{}, f func(string, map[string]interface{}, map[string]bool)) {
+	for i, s := range sections {
+		for j, r := range s.(map[string]interface{})["row"].([]interface{}) {
+			for k, c := range r.(map[string]interface{})["chart"].([]interface{}) {
+				sources := c.(map[string]interface{})["source"].([]interface{})
+				names := map[string]bool{}
+				for _, source := range sources {
+					if name, _ := source.(map[string]interface{})["name"].(string); name != "" {
+						names[name] = true
+					}
+				}
+				for l, source := range sources {
+					f(fmt.Sprintf("section.%d.row.%d.chart.%d.source.%d", i, j, k, l), source.(map[string]interface{}), names)
+				}
+			}
+		}
+	}
+}
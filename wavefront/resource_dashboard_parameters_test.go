@@ -0,0 +1,239 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccWavefrontDashboard_UndefinedParameter(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckWavefrontDashboard_parameters(`ts(cpu.load, env=$${environment})`, "LIST", "prod"),
+				ExpectError: regexp.MustCompile(`section.0.row.0.chart.0.source.0.query references undefined parameter "environment"`),
+			},
+			{
+				Config:      testAccCheckWavefrontDashboard_parameters(`ts(cpu.load, env=$${env})`, "LIST", "staging"),
+				ExpectError: regexp.MustCompile(`parameter_details.0.default_value "staging" must be one of the keys of values_to_readable_strings \[dev, prod\]`),
+			},
+			{
+				Config:      testAccCheckWavefrontDashboard_parameters(`ts(cpu.load, env=$${env})`, "DYNAMIC", "prod"),
+				ExpectError: regexp.MustCompile(`parameter_details.0.query_value must be supplied for DYNAMIC parameters, parameter_details.0.dynamic_field_type must be supplied for DYNAMIC parameters`),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontDashboard_parameters(query, parameterType, defaultValue string) string {
+	return fmt.Sprintf(`
+resource "wavefront_dashboard" "test_dashboard" {
+  name = "Terraform Test Dashboard Parameters"
+  description = "testing, testing"
+  url = "tftestparams"
+  section {
+    name = "section 1"
+    row {
+      chart {
+        name = "chart 1"
+        description = "chart number 1"
+        units = "something per unit"
+        source {
+          name = "source name"
+          query = "%s"
+        }
+        summarization = "MEAN"
+        chart_setting {
+          type = "line"
+        }
+      }
+    }
+  }
+  parameter_details {
+    name = "env"
+    label = "Environment"
+    default_value = "%s"
+    hide_from_view = false
+    parameter_type = "%s"
+    values_to_readable_strings = {
+      prod = "Production"
+      dev = "Development"
+    }
+  }
+  tags = [
+    "terraform"
+  ]
+}
+`, query, defaultValue, parameterType)
+}
+
+func TestResourceDashboard_validateDashboardParameters(t *testing.T) {
+	sections := func(queries ...string) []interface{} {
+		sources := []interface{}{}
+		for i, q := range queries {
+			sources = append(sources, map[string]interface{}{"name": fmt.Sprintf("source%d", i+1), "query": q})
+		}
+		return []interface{}{
+			map[string]interface{}{
+				"row": []interface{}{
+					map[string]interface{}{
+						"chart": []interface{}{
+							map[string]interface{}{"source": sources},
+						},
+					},
+				},
+			},
+		}
+	}
+	param := func(name, parameterType string, extra map[string]interface{}) map[string]interface{} {
+		p := map[string]interface{}{
+			"name":                       name,
+			"parameter_type":             parameterType,
+			"default_value":              "a",
+			"values_to_readable_strings": map[string]interface{}{"a": "A"},
+			"query_value":                "",
+			"dynamic_field_type":         "",
+			"tag_key":                    "",
+		}
+		for k, v := range extra {
+			p[k] = v
+		}
+		return p
+	}
+	known := func(string) bool { return true }
+
+	cases := []struct {
+		name     string
+		sections []interface{}
+		params   []interface{}
+		errs     []string
+		warnings []string
+	}{
+		{
+			"no parameters",
+			sections("ts(cpu.load)"),
+			[]interface{}{},
+			nil,
+			nil,
+		},
+		{
+			"used parameters",
+			sections("ts(cpu.load, env=${env})", "ts(${metric})"),
+			[]interface{}{
+				param("env", "LIST", nil),
+				param("metric", "SIMPLE", nil),
+			},
+			nil,
+			nil,
+		},
+		{
+			"undefined and unused parameters",
+			sections("ts(cpu.load, env=${env})", "ts(mem.used, env=${env})"),
+			[]interface{}{param("environment", "SIMPLE", nil)},
+			[]string{
+				`section.0.row.0.chart.0.source.0.query references undefined parameter "env"`,
+				`section.0.row.0.chart.0.source.1.query references undefined parameter "env"`,
+			},
+			[]string{`parameter "environment" is not used by any source`},
+		},
+		{
+			"sources of the same chart",
+			sections("ts(cpu.load, env=${env})", "ts(cpu.count)", "${source1} / ${source2}"),
+			[]interface{}{param("env", "LIST", nil)},
+			nil,
+			nil,
+		},
+		{
+			"sources of another chart",
+			append(sections("ts(cpu.load)"), sections("${source1} * ${source2}")...),
+			[]interface{}{},
+			[]string{`section.1.row.0.chart.0.source.0.query references undefined parameter "source2"`},
+			nil,
+		},
+		{
+			"invalid queries are left to the ValidateFunc",
+			sections("ts(cpu.load, env=${env}"),
+			[]interface{}{},
+			nil,
+			nil,
+		},
+		{
+			"duplicate parameter",
+			sections("ts(${env})"),
+			[]interface{}{param("env", "SIMPLE", nil), param("env", "SIMPLE", nil)},
+			[]string{`parameter_details.1.name: parameter "env" is defined more than once`},
+			nil,
+		},
+		{
+			"default value not in values",
+			sections("ts(${env})"),
+			[]interface{}{param("env", "LIST", map[string]interface{}{"default_value": "b"})},
+			[]string{`parameter_details.0.default_value "b" must be one of the keys of values_to_readable_strings [a]`},
+			nil,
+		},
+		{
+			"dynamic fields on a list parameter",
+			sections("ts(${env})"),
+			[]interface{}{param("env", "LIST", map[string]interface{}{"query_value": "ts(cpu.load)", "tag_key": "env"})},
+			[]string{
+				"parameter_details.0.query_value cannot be set for LIST parameters",
+				"parameter_details.0.tag_key cannot be set for LIST parameters",
+			},
+			nil,
+		},
+		{
+			"dynamic tag key parameter",
+			sections("ts(${env})"),
+			[]interface{}{param("env", "DYNAMIC", map[string]interface{}{"query_value": "ts(cpu.load)", "dynamic_field_type": "TAG_KEY"})},
+			[]string{"parameter_details.0.tag_key must be supplied for TAG_KEY parameters"},
+			nil,
+		},
+		{
+			"dynamic source parameter",
+			sections("ts(${env})"),
+			[]interface{}{param("env", "DYNAMIC", map[string]interface{}{"query_value": "ts(cpu.load)", "dynamic_field_type": "SOURCE"})},
+			nil,
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		errs, warnings := validateDashboardParameters(c.sections, c.params, known)
+		if !reflect.DeepEqual(errs, c.errs) {
+			t.Errorf("%s: expected errors %q, got %q", c.name, c.errs, errs)
+		}
+		if !reflect.DeepEqual(warnings, c.warnings) {
+			t.Errorf("%s: expected warnings %q, got %q", c.name, c.warnings, warnings)
+		}
+	}
+}
+
+func TestResourceDashboard_validateDashboardParametersUnknown(t *testing.T) {
+	params := []interface{}{
+		map[string]interface{}{
+			"name":                       "env",
+			"parameter_type":             "DYNAMIC",
+			"default_value":              "a",
+			"values_to_readable_strings": map[string]interface{}{},
+			"query_value":                "",
+			"dynamic_field_type":         "SOURCE",
+			"tag_key":                    "",
+		},
+	}
+	unknown := map[string]bool{
+		"parameter_details.0.values_to_readable_strings": true,
+		"parameter_details.0.query_value":                true,
+	}
+	known := func(key string) bool { return !unknown[key] }
+
+	errs, _ := validateDashboardParameters([]interface{}{}, params, known)
+	if len(errs) != 0 {
+		t.Errorf("expected unknown values to be skipped, got %q", errs)
+	}
+}
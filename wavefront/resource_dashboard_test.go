@@ -2,9 +2,13 @@ package wavefront_plugin
 
 import (
 	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/spaceapegames/go-wavefront"
 )
@@ -88,6 +92,20 @@ func TestBuildTerraformRow(t *testing.T) {
 	if len(resultWithCharts["chart"].([]map[string]interface{})) != 2 {
 		t.Errorf("Expected array of length 2, got Array of lenth %d", len(result["chart"].([]map[string]interface{})))
 	}
+
+	namedRow := wavefront.Row{
+		Name:         "row 1",
+		HeightFactor: 100,
+		Charts:       []wavefront.Chart{},
+	}
+
+	namedResult := buildTerraformRow(namedRow)
+	if namedResult["name"] != "row 1" {
+		t.Errorf("expected row 1, got %s", namedResult["name"])
+	}
+	if namedResult["height_factor"] != 100 {
+		t.Errorf("expected 100, got %v", namedResult["height_factor"])
+	}
 }
 
 func TestBuildTerraformChart(t *testing.T) {
@@ -112,6 +130,29 @@ func TestBuildTerraformChart(t *testing.T) {
 		t.Errorf("expected test_chart, got %s", result["units"])
 	}
 
+	chartWithOptions := wavefront.Chart{
+		Name:                   "test_chart",
+		Sources:                []wavefront.Source{},
+		Base:                   10,
+		IncludeObsoleteMetrics: true,
+		InterpolatePoints:      true,
+		NoDefaultEvents:        true,
+	}
+
+	resultWithOptions := buildTerraformChart(chartWithOptions)
+	if resultWithOptions["base"] != 10 {
+		t.Errorf("expected 10, got %v", resultWithOptions["base"])
+	}
+	if resultWithOptions["include_obsolete_metrics"] != true {
+		t.Errorf("expected true, got %v", resultWithOptions["include_obsolete_metrics"])
+	}
+	if resultWithOptions["interpolate_points"] != true {
+		t.Errorf("expected true, got %v", resultWithOptions["interpolate_points"])
+	}
+	if resultWithOptions["no_default_events"] != true {
+		t.Errorf("expected true, got %v", resultWithOptions["no_default_events"])
+	}
+
 	chartWithSources := wavefront.Chart{
 		Name: "I have sources",
 		Sources: []wavefront.Source{
@@ -197,6 +238,8 @@ func TestBuildRows(t *testing.T) {
 	row0["chart"] = []interface{}{}
 
 	row1 := make(map[string]interface{})
+	row1["name"] = "row 1"
+	row1["height_factor"] = 100
 	row1["chart"] = []interface{}{}
 
 	rows := []interface{}{
@@ -208,6 +251,12 @@ func TestBuildRows(t *testing.T) {
 	if len(*result) != 2 {
 		t.Errorf("Expected 2 rows for %d", len(*result))
 	}
+	if (*result)[1].Name != "row 1" {
+		t.Errorf("Expected row 1 for %s", (*result)[1].Name)
+	}
+	if (*result)[1].HeightFactor != 100 {
+		t.Errorf("Expected height factor 100 for %d", (*result)[1].HeightFactor)
+	}
 }
 
 func TestBuildCharts(t *testing.T) {
@@ -229,6 +278,10 @@ func TestBuildCharts(t *testing.T) {
 	chart1["units"] = "unit"
 	chart1["source"] = []interface{}{}
 	chart1["summarization"] = "MEAN"
+	chart1["base"] = 10
+	chart1["include_obsolete_metrics"] = true
+	chart1["interpolate_points"] = true
+	chart1["no_default_events"] = true
 	chart1["chart_setting"] = []interface{}{
 		map[string]interface{}{
 			"type": "linear",
@@ -249,6 +302,13 @@ func TestBuildCharts(t *testing.T) {
 			t.Errorf("Expected chart %d for %s", i, r.Name)
 		}
 	}
+	chart := (*result)[1]
+	if chart.Base != 10 {
+		t.Errorf("Expected base 10 for %d", chart.Base)
+	}
+	if !chart.IncludeObsoleteMetrics || !chart.InterpolatePoints || !chart.NoDefaultEvents {
+		t.Errorf("Expected include_obsolete_metrics, interpolate_points and no_default_events to be set for %+v", chart)
+	}
 }
 
 func TestBuildSources(t *testing.T) {
@@ -276,6 +336,114 @@ func TestBuildSources(t *testing.T) {
 	}
 }
 
+// Every chart and source attribute written to Wavefront is read back as it was configured, so a dashboard doesn't
+// drift between plans. Random charts are built from configuration, read back and compared attribute by attribute.
+func TestChartRoundTrip(t *testing.T) {
+	dashboard := resourceDashboard()
+	chart := dashboard.Schema["section"].Elem.(*schema.Resource).
+		Schema["row"].Elem.(*schema.Resource).
+		Schema["chart"].Elem.(*schema.Resource)
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		raw := map[string]interface{}{
+			"name":        "round trip",
+			"description": "round trip",
+			"url":         "roundtrip",
+			"section": []interface{}{
+				map[string]interface{}{
+					"name": "section 1",
+					"row": []interface{}{
+						map[string]interface{}{
+							"chart": []interface{}{randomBlock(rnd, chart)},
+						},
+					},
+				},
+			},
+		}
+		configured := schema.TestResourceDataRaw(t, dashboard.Schema, raw)
+		terraformSections := configured.Get("section").([]interface{})
+
+		sections := []map[string]interface{}{}
+		for _, section := range *buildSections(&terraformSections) {
+			sections = append(sections, buildTerraformSection(section))
+		}
+		read := schema.TestResourceDataRaw(t, dashboard.Schema, map[string]interface{}{})
+		if err := read.Set("section", sections); err != nil {
+			t.Fatal(err)
+		}
+
+		compareBlocks(t, chart, "section.0.row.0.chart.0", configured, read)
+	}
+}
+
+// compareBlocks reports each attribute of the block at key, and of its nested blocks, which differs between two
+// ResourceData
+func compareBlocks(t *testing.T, block *schema.Resource, key string, expected *schema.ResourceData, actual *schema.ResourceData) {
+	for name, s := range block.Schema {
+		k := key + "." + name
+		if nested, ok := s.Elem.(*schema.Resource); ok {
+			count := expected.Get(k + ".#").(int)
+			if n := actual.Get(k + ".#").(int); n != count {
+				t.Errorf("%s: expected %d blocks, got %d", k, count, n)
+				continue
+			}
+			for i := 0; i < count; i++ {
+				compareBlocks(t, nested, fmt.Sprintf("%s.%d", k, i), expected, actual)
+			}
+			continue
+		}
+		if e, a := expected.Get(k), actual.Get(k); !reflect.DeepEqual(e, a) {
+			t.Errorf("%s: expected %#v, got %#v", k, e, a)
+		}
+	}
+}
+
+// randomBlock returns the configuration of a block with random values for its attributes, leaving some of its
+// optional attributes unset
+func randomBlock(rnd *rand.Rand, block *schema.Resource) map[string]interface{} {
+	raw := map[string]interface{}{}
+	for name, s := range block.Schema {
+		if s.Optional && rnd.Intn(3) == 0 {
+			continue
+		}
+		raw[name] = randomValue(rnd, name, s)
+	}
+	return raw
+}
+
+// randomValue returns a random value of the attribute's type
+func randomValue(rnd *rand.Rand, name string, s *schema.Schema) interface{} {
+	switch s.Type {
+	case schema.TypeBool:
+		return rnd.Intn(2) == 0
+	case schema.TypeInt:
+		return rnd.Intn(1000) + 1
+	case schema.TypeFloat:
+		// Wavefront's chart settings hold float32s, so use values they represent exactly
+		return float64(rnd.Intn(4000)-2000) / 4
+	case schema.TypeList, schema.TypeSet:
+		items := []interface{}{}
+		n := rnd.Intn(3) + 1
+		if s.MaxItems > 0 && n > s.MaxItems {
+			n = s.MaxItems
+		}
+		for i := 0; i < n; i++ {
+			switch elem := s.Elem.(type) {
+			case *schema.Resource:
+				items = append(items, randomBlock(rnd, elem))
+			case *schema.Schema:
+				items = append(items, randomValue(rnd, name, elem))
+			}
+		}
+		return items
+	case schema.TypeMap:
+		return map[string]interface{}{fmt.Sprintf("key %d", rnd.Intn(1000)): name}
+	default:
+		return fmt.Sprintf("%s %d", name, rnd.Intn(1000))
+	}
+}
+
 func TestBuildParameterDetails(t *testing.T) {
 	param0 := make(map[string]interface{})
 	param0["name"] = "source 0"
@@ -306,7 +474,7 @@ func TestBuildParameterDetails(t *testing.T) {
 func TestAccWavefrontDashboard_Basic(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -371,7 +539,7 @@ func TestAccWavefrontDashboard_Basic(t *testing.T) {
 func TestAccWavefrontDashboard_Updated(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -409,7 +577,7 @@ func TestAccWavefrontDashboard_Updated(t *testing.T) {
 func TestAccWavefrontDashboard_Multiple(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -433,7 +601,7 @@ func TestAccWavefrontDashboard_Multiple(t *testing.T) {
 func TestAccWavefrontDashboard_ListParam(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -459,7 +627,7 @@ func TestAccWavefrontDashboard_ListParam(t *testing.T) {
 func TestAccWavefrontDashboard_DynamicSourceParam(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -487,7 +655,7 @@ func TestAccWavefrontDashboard_DynamicSourceParam(t *testing.T) {
 func TestAccWavefrontDashboard_DynamicSourceTagParam(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -515,7 +683,7 @@ func TestAccWavefrontDashboard_DynamicSourceTagParam(t *testing.T) {
 func TestAccWavefrontDashboard_DynamicMetricNameParam(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -543,7 +711,7 @@ func TestAccWavefrontDashboard_DynamicMetricNameParam(t *testing.T) {
 func TestAccWavefrontDashboard_DynamicTagKeyParam(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -573,7 +741,7 @@ func TestAccWavefrontDashboard_DynamicTagKeyParam(t *testing.T) {
 func TestAccWavefrontDashboard_DynamicMatchingSourceTagParam(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -601,7 +769,7 @@ func TestAccWavefrontDashboard_DynamicMatchingSourceTagParam(t *testing.T) {
 func TestAccWavefrontDashboard_Linear_ChartSettings(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -668,7 +836,7 @@ func TestAccWavefrontDashboard_Linear_ChartSettings(t *testing.T) {
 func TestAccWavefrontDashboard_Table_ChartSettings(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -747,7 +915,7 @@ func TestAccWavefrontDashboard_Table_ChartSettings(t *testing.T) {
 func TestAccWavefrontDashboard_Sparkline_ChartSettings(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -842,7 +1010,7 @@ func TestAccWavefrontDashboard_Sparkline_ChartSettings(t *testing.T) {
 func TestAccWavefrontDashboard_Markdown_ChartSettings(t *testing.T) {
 	var record wavefront.Dashboard
 
-	resource.Test(t, resource.TestCase{
+	testAccResourceTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
@@ -911,6 +1079,184 @@ func TestAccWavefrontDashboard_Markdown_ChartSettings(t *testing.T) {
 	})
 }
 
+func TestAccWavefrontDashboard_ChartAndRowOptions(t *testing.T) {
+	var record wavefront.Dashboard
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontDashboard_ChartAndRowOptions(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontDashboardExists("wavefront_dashboard.options_dash", &record),
+					testAccCheckWavefrontDashboardChartAndRowOptions(&record),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.name", "row 1"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.height_factor", "100"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.chart.0.base", "10"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.chart.0.include_obsolete_metrics", "true"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.chart.0.interpolate_points", "true"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.chart.0.no_default_events", "true"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.chart.0.description", "chart number 1"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.chart.0.source.0.source_description", "source number 1"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.0.chart.0.source.0.source_color", "#80a8e5"),
+					// Rows and charts which set none of the options have Wavefront's defaults
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.1.name", ""),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.1.height_factor", "50"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.1.chart.0.base", "1"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.options_dash", "section.0.row.1.chart.0.include_obsolete_metrics", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontDashboardChartAndRowOptions(dashboard *wavefront.Dashboard) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		row := dashboard.Sections[0].Rows[0]
+		if row.Name != "row 1" || row.HeightFactor != 100 {
+			return fmt.Errorf("Bad row name or height factor: %s %d", row.Name, row.HeightFactor)
+		}
+		chart := row.Charts[0]
+		if chart.Base != 10 || !chart.IncludeObsoleteMetrics || !chart.InterpolatePoints || !chart.NoDefaultEvents {
+			return fmt.Errorf("Bad chart options: %+v", chart)
+		}
+		if chart.Description != "chart number 1" {
+			return fmt.Errorf("Bad chart description: %s", chart.Description)
+		}
+		if source := chart.Sources[0]; source.SourceDescription != "source number 1" || source.SourceColor != "#80a8e5" {
+			return fmt.Errorf("Bad source description or color: %s %s", source.SourceDescription, source.SourceColor)
+		}
+		return nil
+	}
+}
+
+func TestAccWavefrontDashboard_Settings(t *testing.T) {
+	var record wavefront.Dashboard
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontDashboard_settings(`
+  event_filter_type = "BYDASHBOARD"
+  event_query = "events(name=\"deploy\")"
+  display_description = true
+  hidden = true
+  chart_title_bg_color = "rgba(0,0,0,1)"
+  chart_title_color = "rgba(255,255,255,1)"
+  chart_title_scalar = 120
+  default_start_time = 1577836800000
+  default_end_time = 1577923200000
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontDashboardExists("wavefront_dashboard.settings_dash", &record),
+					testAccCheckWavefrontDashboardSettings(&record),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "event_filter_type", "BYDASHBOARD"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "event_query", `events(name="deploy")`),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "display_description", "true"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "hidden", "true"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "chart_title_bg_color", "rgba(0,0,0,1)"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "chart_title_color", "rgba(255,255,255,1)"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "chart_title_scalar", "120"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "default_start_time", "1577836800000"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "default_end_time", "1577923200000"),
+				),
+			},
+			{
+				Config: testAccCheckWavefrontDashboard_settings(`
+  default_time_window = "2h"
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontDashboardExists("wavefront_dashboard.settings_dash", &record),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "event_filter_type", "BYCHART"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "event_query", ""),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "hidden", "false"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "default_time_window", "2h"),
+					resource.TestCheckResourceAttr(
+						"wavefront_dashboard.settings_dash", "default_start_time", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontDashboard_InvalidSettings(t *testing.T) {
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckWavefrontDashboard_settings(`event_filter_type = "BYSOURCE"`),
+				ExpectError: regexp.MustCompile(`event_filter_type must be one of BYCHART, AUTOMATIC, ALL, NONE, BYDASHBOARD, BYCHARTANDDASHBOARD, got "BYSOURCE"`),
+			},
+			{
+				Config:      testAccCheckWavefrontDashboard_settings(`event_query = "ts(cpu.load)"`),
+				ExpectError: regexp.MustCompile(`event_query is not a valid Wavefront events query: line 1, column 1: expected an events\(\) expression`),
+			},
+			{
+				Config:      testAccCheckWavefrontDashboard_settings(`default_time_window = "2 hours"`),
+				ExpectError: regexp.MustCompile(`default_time_window must be a number of s, m, h, d or w such as 2h, got "2 hours"`),
+			},
+			{
+				Config: testAccCheckWavefrontDashboard_settings(`default_start_time = 1577923200000
+  default_end_time = 1577836800000`),
+				ExpectError: regexp.MustCompile("default_end_time of dashboard tftestcreate must be after its default_start_time"),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontDashboardSettings(dashboard *wavefront.Dashboard) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if dashboard.EventFilterType != "BYDASHBOARD" || dashboard.EventQuery != `events(name="deploy")` {
+			return fmt.Errorf("Bad event settings: %s %s", dashboard.EventFilterType, dashboard.EventQuery)
+		}
+		if !dashboard.DisplayDescription || !dashboard.Hidden {
+			return fmt.Errorf("Bad display_description or hidden: %t %t", dashboard.DisplayDescription, dashboard.Hidden)
+		}
+		if dashboard.ChartTitleBgColor != "rgba(0,0,0,1)" || dashboard.ChartTitleColor != "rgba(255,255,255,1)" ||
+			dashboard.ChartTitleScalar != 120 {
+			return fmt.Errorf("Bad chart title settings: %s %s %d",
+				dashboard.ChartTitleBgColor, dashboard.ChartTitleColor, dashboard.ChartTitleScalar)
+		}
+		if dashboard.DefaultStartTime != 1577836800000 || dashboard.DefaultEndTime != 1577923200000 {
+			return fmt.Errorf("Bad default time range: %d %d", dashboard.DefaultStartTime, dashboard.DefaultEndTime)
+		}
+		return nil
+	}
+}
+
 func testAccCheckWavefrontDashboardDestroy(s *terraform.State) error {
 
 	dashboards := testAccProvider.Meta().(*wavefrontClient).client.Dashboards()
@@ -1775,3 +2121,85 @@ resource "wavefront_dashboard" "chart_settings_dash" {
 }
 `)
 }
+
+func testAccCheckWavefrontDashboard_ChartAndRowOptions() string {
+	return fmt.Sprintf(`
+resource "wavefront_dashboard" "options_dash" {
+  name = "Terraform Chart and Row Options"
+  description = "testing, testing"
+  url = "tftestcreate"
+  section {
+    name = "section 1"
+    row {
+      name = "row 1"
+      height_factor = 100
+      chart {
+        name = "chart 1"
+        description = "chart number 1"
+        units = "something per unit"
+        source {
+          name = "source name"
+          query = "ts()"
+          source_description = "source number 1"
+          source_color = "#80a8e5"
+        }
+        summarization = "MEAN"
+        base = 10
+        include_obsolete_metrics = true
+        interpolate_points = true
+        no_default_events = true
+        chart_setting {
+          type = "line"
+        }
+      }
+    }
+    row {
+      chart {
+        name = "chart 2"
+        units = "something per unit"
+        source {
+          name = "source name"
+          query = "ts()"
+        }
+        summarization = "MEAN"
+        chart_setting {
+          type = "line"
+        }
+      }
+    }
+  }
+  tags = [
+    "terraform",
+    "test"
+  ]
+}
+`)
+}
+
+func testAccCheckWavefrontDashboard_settings(settings string) string {
+	return fmt.Sprintf(`
+resource "wavefront_dashboard" "settings_dash" {
+  name = "Terraform Dashboard Settings"
+  description = "testing, testing"
+  url = "tftestcreate"
+  section {
+    name = "section 1"
+    row {
+      chart {
+        name = "chart 1"
+        units = "something per unit"
+        source {
+          name = "source name"
+          query = "ts()"
+        }
+        summarization = "MEAN"
+        chart_setting {
+          type = "line"
+        }
+      }
+    }
+  }
+  %s
+}
+`, settings)
+}
@@ -0,0 +1,212 @@
+package wavefront_plugin
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func resourceEvent() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceEventCreate,
+		Read:   resourceEventRead,
+		Update: resourceEventUpdate,
+		Delete: resourceEventDelete,
+		Importer: &schema.ResourceImporter{
+			State: importStateWithCluster,
+		},
+		Timeouts: resourceTimeouts(),
+
+		Schema: map[string]*schema.Schema{
+			"cluster": clusterArgumentSchema(true),
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"severity": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "UNCLASSIFIED",
+				ValidateFunc: validateStringIn("INFO", "WARN", "SEVERE", "UNCLASSIFIED"),
+			},
+			// e.g. "Deploy" or "Maintenance"
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"details": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			// Epoch milliseconds. Defaults to the time the event is created
+			"start_time": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			// Epoch milliseconds. Unset for ongoing events, set by Wavefront when an event is closed
+			"end_time": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"instantaneous": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			// By default destroying an ongoing event closes it and ended events are left in Wavefront.
+			"delete_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func buildEvent(d *schema.ResourceData, e *wavefront.Event) {
+	var tags []string
+	for _, tag := range d.Get("tags").(*schema.Set).List() {
+		tags = append(tags, tag.(string))
+	}
+
+	e.Name = d.Get("name").(string)
+	e.Severity = d.Get("severity").(string)
+	e.Type = d.Get("type").(string)
+	e.Details = d.Get("details").(string)
+	e.Tags = tags
+	e.StartTime = int64(d.Get("start_time").(int))
+	e.EndTime = int64(d.Get("end_time").(int))
+	e.Instantaneous = d.Get("instantaneous").(bool)
+}
+
+func resourceEventCreate(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutCreate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	events := c.client.Events()
+
+	e := &wavefront.Event{}
+	buildEvent(d, e)
+
+	// Create the event on Wavefront
+	err = events.Create(e)
+	if err != nil {
+		return wavefrontError("creating", "Event", d.Get("name"), err)
+	}
+
+	d.SetId(*e.ID)
+
+	return resourceEventRead(d, m)
+}
+
+func resourceEventRead(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutRead)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	events := c.client.Events()
+
+	e, err := events.FindByID(d.Id())
+	if eventNotFound(err) {
+		log.Printf("[WARN] Wavefront Event %s not found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return wavefrontError("reading", "Event", d.Id(), err)
+	}
+
+	d.Set("name", e.Name)
+	d.Set("severity", e.Severity)
+	d.Set("type", e.Type)
+	d.Set("details", e.Details)
+	d.Set("tags", e.Tags)
+	d.Set("start_time", e.StartTime)
+	d.Set("end_time", e.EndTime)
+	d.Set("instantaneous", e.Instantaneous)
+
+	return nil
+}
+
+func resourceEventUpdate(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutUpdate)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	events := c.client.Events()
+
+	e, err := events.FindByID(d.Id())
+	if err != nil {
+		return wavefrontError("reading", "Event", d.Id(), err)
+	}
+	buildEvent(d, e)
+
+	// Update the event on Wavefront
+	err = events.Update(e)
+	if err != nil {
+		return wavefrontError("updating", "Event", d.Id(), err)
+	}
+
+	return resourceEventRead(d, m)
+}
+
+func resourceEventDelete(d *schema.ResourceData, m interface{}) error {
+	c, cancel, err := clientForOperation(d, m, schema.TimeoutDelete)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	events := c.client.Events()
+
+	e, err := events.FindByID(d.Id())
+	if eventNotFound(err) {
+		log.Printf("[WARN] Wavefront Event %s already deleted", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return wavefrontError("reading", "Event", d.Id(), err)
+	}
+
+	if d.Get("delete_on_destroy").(bool) {
+		err = events.Delete(e)
+		if err != nil {
+			return wavefrontError("deleting", "Event", d.Id(), err)
+		}
+	} else if eventOngoing(e, time.Now()) {
+		err = events.Close(e)
+		if err != nil {
+			return wavefrontError("closing", "Event", d.Id(), err)
+		}
+	} else {
+		log.Printf("[INFO] Wavefront Event %s has ended, removing it from state only", d.Id())
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// eventOngoing reports whether an event has not yet ended at the given time
+func eventOngoing(e *wavefront.Event, now time.Time) bool {
+	return e.EndTime == 0 || e.EndTime > now.Unix()*1000
+}
+
+// eventNotFound reports whether Events.FindByID failed because the event does not exist. FindByID searches for the
+// event, so returns its own error rather than a 404.
+func eventNotFound(err error) bool {
+	return err != nil && (isNotFound(err) || strings.HasPrefix(err.Error(), "no event found"))
+}
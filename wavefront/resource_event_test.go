@@ -0,0 +1,288 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestAccWavefrontEvent_Basic(t *testing.T) {
+	var record wavefront.Event
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontEventClosed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontEvent_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontEventExists("wavefront_event.test_event", &record),
+					testAccCheckWavefrontEventAttributes(&record),
+
+					// Check against state that the attributes are as we expect
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "name", "Terraform Test Event"),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "severity", "INFO"),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "type", "Deploy"),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "details", "Deploying version 1"),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "end_time", "0"),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "tags.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontEvent_Updated(t *testing.T) {
+	var record wavefront.Event
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontEventClosed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontEvent_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontEventExists("wavefront_event.test_event", &record),
+					testAccCheckWavefrontEventAttributes(&record),
+				),
+			},
+			{
+				Config: testAccCheckWavefrontEvent_new_value(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontEventExists("wavefront_event.test_event", &record),
+					testAccCheckWavefrontEventAttributesUpdated(&record),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "details", "Deploying version 2"),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "severity", "WARN"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontEvent_Instantaneous(t *testing.T) {
+	var record wavefront.Event
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontEventClosed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontEvent_instantaneous(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontEventExists("wavefront_event.test_event", &record),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "instantaneous", "true"),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "start_time", "1500000000000"),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "end_time", "1500000000001"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontEvent_DeleteOnDestroy(t *testing.T) {
+	var record wavefront.Event
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontEventDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontEvent_deleteOnDestroy(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontEventExists("wavefront_event.test_event", &record),
+					resource.TestCheckResourceAttr(
+						"wavefront_event.test_event", "delete_on_destroy", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceEvent_eventOngoing(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+
+	cases := []struct {
+		name    string
+		endTime int64
+		ongoing bool
+	}{
+		{"no end time", 0, true},
+		{"ends in the future", 1500000060000, true},
+		{"ended", 1499999940000, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if eventOngoing(&wavefront.Event{EndTime: c.endTime}, now) != c.ongoing {
+				t.Errorf("expected ongoing to be %t for end time %d", c.ongoing, c.endTime)
+			}
+		})
+	}
+}
+
+// Destroying an event without delete_on_destroy should leave it in Wavefront, closed
+func testAccCheckWavefrontEventClosed(s *terraform.State) error {
+
+	events := testAccProvider.Meta().(*wavefrontClient).client.Events()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "wavefront_event" {
+			continue
+		}
+
+		event, err := events.FindByID(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error finding Wavefront Event %s", err)
+		}
+		if eventOngoing(event, time.Now()) {
+			return fmt.Errorf("Event %s is still ongoing", rs.Primary.ID)
+		}
+
+		// tidy up the closed event
+		err = events.Delete(event)
+		if err != nil {
+			return fmt.Errorf("Error deleting Wavefront Event %s", err)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckWavefrontEventDestroy(s *terraform.State) error {
+
+	events := testAccProvider.Meta().(*wavefrontClient).client.Events()
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "wavefront_event" {
+			continue
+		}
+
+		_, err := events.FindByID(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Event still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckWavefrontEventAttributes(event *wavefront.Event) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if event.Details != "Deploying version 1" {
+			return fmt.Errorf("Bad value: %s", event.Details)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckWavefrontEventAttributesUpdated(event *wavefront.Event) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+
+		if event.Details != "Deploying version 2" {
+			return fmt.Errorf("Bad value: %s", event.Details)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckWavefrontEventExists(n string, event *wavefront.Event) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Record ID is set")
+		}
+
+		events := testAccProvider.Meta().(*wavefrontClient).client.Events()
+		tmpEvent, err := events.FindByID(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("Error finding Wavefront Event %s", err)
+		}
+
+		if strconv.FormatInt(tmpEvent.StartTime, 10) != rs.Primary.Attributes["start_time"] {
+			return fmt.Errorf("Bad start_time: %s", rs.Primary.Attributes["start_time"])
+		}
+
+		*event = *tmpEvent
+
+		return nil
+	}
+}
+
+func testAccCheckWavefrontEvent_basic() string {
+	return fmt.Sprintf(`
+resource "wavefront_event" "test_event" {
+  name = "Terraform Test Event"
+  severity = "INFO"
+  type = "Deploy"
+  details = "Deploying version 1"
+  tags = [
+    "terraform",
+    "test"
+  ]
+}
+`)
+}
+
+func testAccCheckWavefrontEvent_new_value() string {
+	return fmt.Sprintf(`
+resource "wavefront_event" "test_event" {
+  name = "Terraform Test Event"
+  severity = "WARN"
+  type = "Deploy"
+  details = "Deploying version 2"
+  tags = [
+    "terraform",
+    "test"
+  ]
+}
+`)
+}
+
+func testAccCheckWavefrontEvent_instantaneous() string {
+	return fmt.Sprintf(`
+resource "wavefront_event" "test_event" {
+  name = "Terraform Test Instantaneous Event"
+  type = "Deploy"
+  start_time = 1500000000000
+  instantaneous = true
+}
+`)
+}
+
+func testAccCheckWavefrontEvent_deleteOnDestroy() string {
+	return fmt.Sprintf(`
+resource "wavefront_event" "test_event" {
+  name = "Terraform Test Deleted Event"
+  type = "Maintenance"
+  delete_on_destroy = true
+}
+`)
+}
@@ -0,0 +1,267 @@
+package wavefront_plugin
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestAccWavefrontAlert_Throttled(t *testing.T) {
+	if testAccMock == nil {
+		t.Skip("throttling is simulated by the mock Wavefront API")
+	}
+	var record wavefront.Alert
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { testAccMock.throttle(2) },
+				Config:    testAccCheckWavefrontAlert_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					func(*terraform.State) error {
+						testAccMock.mu.Lock()
+						defer testAccMock.mu.Unlock()
+						if testAccMock.throttled != 0 {
+							return fmt.Errorf("expected the throttled requests to be retried, %d remaining", testAccMock.throttled)
+						}
+						return nil
+					},
+					testAccCheckWavefrontAlertExists("wavefront_alert.test_alert", &record),
+				),
+			},
+		},
+	})
+}
+
+// A stand-in for Wavefront which returns each of statuses in turn, then 200
+type retryTestServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	headers  map[string]string
+	bodies   []string
+}
+
+func newRetryTestServer(headers map[string]string, statuses ...int) *retryTestServer {
+	s := &retryTestServer{statuses: statuses, headers: headers}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.bodies = append(s.bodies, string(body))
+		if len(s.statuses) > 0 {
+			for k, v := range s.headers {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(s.statuses[0])
+			s.statuses = s.statuses[1:]
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	return s
+}
+
+func (s *retryTestServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+func testRetryTransport(maxRetries int) *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{
+			next:       http.DefaultTransport,
+			maxRetries: maxRetries,
+			minWait:    time.Millisecond,
+			maxWait:    5 * time.Millisecond,
+		},
+	}
+}
+
+func TestRetryTransport_retriesAndReplaysBody(t *testing.T) {
+	s := newRetryTestServer(nil, 429, 502, 503, 504)
+	defer s.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, s.URL, nil)
+	req.Body = ioutil.NopCloser(strings.NewReader(`{"name":"alert"}`))
+	resp, err := testRetryTransport(4).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Errorf("expected a 200 after retrying, got %d", resp.StatusCode)
+	}
+	if s.requests() != 5 {
+		t.Errorf("expected 5 requests, got %d", s.requests())
+	}
+	for i, body := range s.bodies {
+		if body != `{"name":"alert"}` {
+			t.Errorf("expected the body to be replayed on request %d, got %q", i, body)
+		}
+	}
+}
+
+func TestRetryTransport_givesUp(t *testing.T) {
+	s := newRetryTestServer(nil, 503, 503, 503)
+	defer s.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+	resp, err := testRetryTransport(2).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 503 {
+		t.Errorf("expected the last 503 to be returned, got %d", resp.StatusCode)
+	}
+	if s.requests() != 3 {
+		t.Errorf("expected 3 requests, got %d", s.requests())
+	}
+}
+
+func TestRetryTransport_doesNotRetry(t *testing.T) {
+	cases := []struct {
+		method string
+		status int
+	}{
+		{http.MethodGet, 400},
+		{http.MethodGet, 404},
+		{http.MethodGet, 500},
+		{http.MethodPost, 502},
+		{http.MethodPost, 504},
+	}
+
+	for _, c := range cases {
+		s := newRetryTestServer(nil, c.status)
+		req, _ := http.NewRequest(c.method, s.URL, nil)
+		resp, err := testRetryTransport(3).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != c.status || s.requests() != 1 {
+			t.Errorf("expected %s returning %d not to be retried, got %d after %d requests",
+				c.method, c.status, resp.StatusCode, s.requests())
+		}
+		s.Close()
+	}
+}
+
+func TestRetryTransport_postRetriedWhenThrottled(t *testing.T) {
+	s := newRetryTestServer(nil, 429, 503)
+	defer s.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, s.URL, nil)
+	resp, err := testRetryTransport(3).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 200 || s.requests() != 3 {
+		t.Errorf("expected a throttled POST to be retried, got %d after %d requests", resp.StatusCode, s.requests())
+	}
+}
+
+func TestRetryTransport_contextCancelled(t *testing.T) {
+	s := newRetryTestServer(map[string]string{"Retry-After": "60"}, 429)
+	defer s.Close()
+
+	client := &http.Client{
+		Transport: &retryTransport{next: http.DefaultTransport, maxRetries: 3, minWait: time.Second, maxWait: time.Minute},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+
+	start := time.Now()
+	_, err := client.Do(req.WithContext(ctx))
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("expected the wait to be cancelled, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("expected the wait to end when the context was cancelled")
+	}
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	rt := &retryTransport{minWait: time.Second, maxWait: 10 * time.Second}
+	resp := &http.Response{Header: http.Header{}}
+
+	for attempt, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		for i := 0; i < 20; i++ {
+			wait := rt.backoff(attempt, resp)
+			if wait < max/2 || wait > max {
+				t.Errorf("attempt %d: expected a wait between %s and %s, got %s", attempt, max/2, max, wait)
+			}
+		}
+	}
+
+	resp.Header.Set("Retry-After", "3")
+	if wait := rt.backoff(0, resp); wait != 3*time.Second {
+		t.Errorf("expected Retry-After to be honored, got %s", wait)
+	}
+	resp.Header.Set("Retry-After", "120")
+	if wait := rt.backoff(0, resp); wait != 10*time.Second {
+		t.Errorf("expected Retry-After to be capped at the max wait, got %s", wait)
+	}
+}
+
+func TestRetryTransport_backoffNoWait(t *testing.T) {
+	rt := &retryTransport{}
+	if wait := rt.backoff(2, &http.Response{Header: http.Header{}}); wait != 0 {
+		t.Errorf("expected no wait with retry_max_wait of 0, got %s", wait)
+	}
+}
+
+func TestProviderConfigure_retryWaits(t *testing.T) {
+	_, err := providerConfigure(testProviderData(t, map[string]interface{}{
+		"retry_min_wait": 10,
+		"retry_max_wait": 5,
+	}), nil)
+	if err == nil || err.Error() != "retry_min_wait (10) must not be greater than retry_max_wait (5)" {
+		t.Errorf("expected an error for retry_min_wait greater than retry_max_wait, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header   string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"Tue, 01 Oct 2019 12:00:30 GMT", 30 * time.Second, true},
+		{"Tue, 01 Oct 2019 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, c := range cases {
+		resp := &http.Response{Header: http.Header{}}
+		resp.Header.Set("Retry-After", c.header)
+		wait, ok := retryAfter(resp, now)
+		if wait != c.expected || ok != c.ok {
+			t.Errorf("Retry-After %q: expected %s, %t, got %s, %t", c.header, c.expected, c.ok, wait, ok)
+		}
+	}
+}
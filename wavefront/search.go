@@ -0,0 +1,72 @@
+package wavefront_plugin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+// The largest page of results the search API returns, and the default search_page_size
+const maxSearchPageSize = 1000
+
+// searchPages runs a search for the objects of searchType matching conditions, walking every page of results
+// pageSize at a time. Each page is passed to appendPage to be decoded.
+func searchPages(client *wavefront.Client, searchType string, conditions []*wavefront.SearchCondition, pageSize int,
+	appendPage func(items json.RawMessage) (int, error)) error {
+	search := client.NewSearch(searchType, &wavefront.SearchParams{
+		Conditions: conditions,
+		Limit:      pageSize,
+	})
+	for {
+		resp, err := search.Execute()
+		if err != nil {
+			return err
+		}
+		n, err := appendPage(resp.Response.Items)
+		if err != nil {
+			return fmt.Errorf("error decoding the %s search results. %s", searchType, err)
+		}
+		// An empty page ends the search, should Wavefront report more items than it returns
+		if !resp.Response.MoreItems || n == 0 {
+			return nil
+		}
+		search.Params.Offset = resp.NextOffset
+	}
+}
+
+// findAlerts returns every alert matching conditions, from all pages of results
+func (c *wavefrontClient) findAlerts(conditions []*wavefront.SearchCondition) ([]*wavefront.Alert, error) {
+	var results []*wavefront.Alert
+	err := searchPages(&c.client, "alert", conditions, c.searchPageSize, func(items json.RawMessage) (int, error) {
+		var page []*wavefront.Alert
+		err := json.Unmarshal(items, &page)
+		results = append(results, page...)
+		return len(page), err
+	})
+	return results, err
+}
+
+// findTargets returns every alert target matching conditions, from all pages of results
+func (c *wavefrontClient) findTargets(conditions []*wavefront.SearchCondition) ([]*wavefront.Target, error) {
+	var results []*wavefront.Target
+	err := searchPages(&c.client, "notificant", conditions, c.searchPageSize, func(items json.RawMessage) (int, error) {
+		var page []*wavefront.Target
+		err := json.Unmarshal(items, &page)
+		results = append(results, page...)
+		return len(page), err
+	})
+	return results, err
+}
+
+// findDashboards returns every dashboard matching conditions, from all pages of results
+func (c *wavefrontClient) findDashboards(conditions []*wavefront.SearchCondition) ([]*wavefront.Dashboard, error) {
+	var results []*wavefront.Dashboard
+	err := searchPages(&c.client, "dashboard", conditions, c.searchPageSize, func(items json.RawMessage) (int, error) {
+		var page []*wavefront.Dashboard
+		err := json.Unmarshal(items, &page)
+		results = append(results, page...)
+		return len(page), err
+	})
+	return results, err
+}
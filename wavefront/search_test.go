@@ -0,0 +1,52 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestFindAlerts(t *testing.T) {
+	m := newMockWavefront()
+	defer m.Close()
+	client, err := wavefront.NewClient(&wavefront.Config{
+		Address:       m.Address(),
+		Token:         mockWavefrontToken,
+		SkipTLSVerify: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 7; i++ {
+		tags := []string{"terraform"}
+		if i%2 == 0 {
+			tags = append(tags, "even")
+		}
+		a := &wavefront.Alert{Name: fmt.Sprintf("alert %d", i), Condition: "ts(cpu) > 80", Minutes: 5, Severity: "WARN", Tags: tags}
+		if err := client.Alerts().Create(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &wavefrontClient{client: *client, searchPageSize: 3}
+	alerts, err := c.findAlerts(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 7 {
+		t.Errorf("expected all 7 alerts, got %d", len(alerts))
+	}
+	if searches := m.countRequests("POST", "/api/v2/search/alert"); searches != 3 {
+		t.Errorf("expected 3 pages of alerts, got %d searches", searches)
+	}
+
+	alerts, err = c.findAlerts([]*wavefront.SearchCondition{{Key: "tags", Value: "even", MatchingMethod: "EXACT"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 4 {
+		t.Errorf("expected the 4 alerts tagged even, got %d", len(alerts))
+	}
+}
@@ -0,0 +1,90 @@
+package wavefront_plugin
+
+import (
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// mergeTags returns the tags with the provider's default_tags added, sorted and without duplicates
+func mergeTags(tags []string, defaults []string) []string {
+	seen := map[string]bool{}
+	merged := []string{}
+	for _, list := range [][]string{tags, defaults} {
+		for _, tag := range list {
+			if !seen[tag] {
+				seen[tag] = true
+				merged = append(merged, tag)
+			}
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// withoutDefaultTags returns the tags read from Wavefront without the default_tags which are not also configured on
+// the resource, so that the resource's tags attribute only holds the tags it configures
+func withoutDefaultTags(tags []string, configured []string, defaults []string) []string {
+	keep := map[string]bool{}
+	for _, tag := range configured {
+		keep[tag] = true
+	}
+	defaultOnly := map[string]bool{}
+	for _, tag := range defaults {
+		defaultOnly[tag] = !keep[tag]
+	}
+
+	result := []string{}
+	for _, tag := range tags {
+		if !defaultOnly[tag] {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// setStrings returns the strings of a TypeSet attribute
+func setStrings(v interface{}) []string {
+	tags := []string{}
+	if set, ok := v.(*schema.Set); ok {
+		for _, tag := range set.List() {
+			tags = append(tags, tag.(string))
+		}
+	}
+	return tags
+}
+
+// defaultTagsOf returns the provider's default_tags from the provider meta
+func defaultTagsOf(m interface{}) []string {
+	if c, ok := m.(*wavefrontClient); ok {
+		return c.defaultTags
+	}
+	return nil
+}
+
+// customizeDiffTagsAll plans tags_all as the resource's tags merged with the provider's default_tags
+func customizeDiffTagsAll(d *schema.ResourceDiff, tags []string, known bool, m interface{}) error {
+	if !known {
+		return d.SetNewComputed("tags_all")
+	}
+	return setComputedStrings(d, "tags_all", mergeTags(tags, defaultTagsOf(m)))
+}
+
+// setComputedStrings plans a computed list or set attribute as the values. An empty value is planned by clearing
+// the attribute, as an empty computed attribute is otherwise planned as unknown on every run.
+func setComputedStrings(d *schema.ResourceDiff, key string, values []string) error {
+	if len(values) == 0 {
+		return d.Clear(key)
+	}
+	return d.SetNew(key, values)
+}
+
+// tagsAllSchema is the schema of the computed tags_all attribute of resources with tags
+func tagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The tags of the resource, including the provider's default_tags",
+	}
+}
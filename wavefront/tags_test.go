@@ -0,0 +1,102 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestMergeTags(t *testing.T) {
+	merged := mergeTags([]string{"terraform", "b"}, []string{"team-a", "terraform"})
+	expected := []string{"b", "team-a", "terraform"}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("expected %v, got %v", expected, merged)
+	}
+
+	if merged := mergeTags(nil, nil); len(merged) != 0 {
+		t.Errorf("expected no tags, got %v", merged)
+	}
+}
+
+func TestWithoutDefaultTags(t *testing.T) {
+	read := []string{"b", "team-a", "terraform", "added-in-ui"}
+	tags := withoutDefaultTags(read, []string{"terraform", "b"}, []string{"team-a", "terraform"})
+	// terraform is configured on the resource as well as by default, so is kept
+	expected := []string{"b", "terraform", "added-in-ui"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected %v, got %v", expected, tags)
+	}
+}
+
+func TestAccWavefrontAlert_DefaultTags(t *testing.T) {
+	var record wavefront.Alert
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontProvider_defaultTags() + testAccCheckWavefrontAlert_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontAlertExists("wavefront_alert.test_alert", &record),
+					testAccCheckWavefrontTags(&record.Tags, "a", "b", "c", "team-observability", "terraform", "test"),
+					resource.TestCheckResourceAttr("wavefront_alert.test_alert", "tags.#", "5"),
+					resource.TestCheckResourceAttr("wavefront_alert.test_alert", "tags_all.#", "6"),
+				),
+			},
+			{
+				// Without default_tags the tag is removed
+				Config: testAccCheckWavefrontAlert_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontAlertExists("wavefront_alert.test_alert", &record),
+					testAccCheckWavefrontTags(&record.Tags, "a", "b", "c", "terraform", "test"),
+					resource.TestCheckResourceAttr("wavefront_alert.test_alert", "tags_all.#", "5"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccWavefrontDashboardJson_DefaultTags(t *testing.T) {
+	var record wavefront.Dashboard
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontDashboardJsonDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontProvider_defaultTags() + testAccCheckWavefrontDashboardJson_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckWavefrontDashboardJsonExists("wavefront_dashboard_json.test_dashboard_json", &record),
+					testAccCheckWavefrontTags(&record.Tags, "team-observability", "terraform"),
+					resource.TestCheckResourceAttr("wavefront_dashboard_json.test_dashboard_json", "tags_all.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckWavefrontTags(tags *[]string, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if got := mergeTags(*tags, nil); !reflect.DeepEqual(got, expected) {
+			return fmt.Errorf("expected the tags %v in Wavefront, got %v", expected, got)
+		}
+		return nil
+	}
+}
+
+func testAccCheckWavefrontProvider_defaultTags() string {
+	return fmt.Sprintf(`
+provider "wavefront" {
+  default_tags {
+    tags = ["team-observability", "terraform"]
+  }
+}
+`)
+}
@@ -0,0 +1,130 @@
+package wavefront_plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// The output of each token_command, keyed by the command's arguments. Terraform configures the provider more than
+// once in a run, so the command is run once and its token reused for the lifetime of the provider process.
+var tokenCommandCache = struct {
+	sync.Mutex
+	tokens map[string]string
+}{tokens: map[string]string{}}
+
+// How long a token_command may run before it is killed, so that a hung helper doesn't hang the provider
+var tokenCommandTimeout = time.Minute
+
+// resolveToken returns the API token from whichever of token_command, token_file or token is set. When a
+// token_exchange block is set it is the credential exchanged for access tokens.
+func resolveToken(d *schema.ResourceData) (string, error) {
+	command, path, token := d.Get("token_command").([]interface{}), d.Get("token_file").(string), d.Get("token").(string)
+	// Only the provider's token and token_file have environment defaults, which may conflict with the configuration
+	if err := checkTokenSettings(command, path, token); err != nil {
+		return "", fmt.Errorf("%s. token and token_file may be set by WAVEFRONT_TOKEN and WAVEFRONT_TOKEN_FILE", err)
+	}
+	token, err := tokenFromSettings(command, path, token)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		return token, nil
+	}
+	// Exchanging client credentials for access tokens needs no token
+	if exchangesClientCredentials(d.Get("token_exchange").([]interface{})) {
+		return "", nil
+	}
+	return "", fmt.Errorf("one of token, token_file or token_command must be set")
+}
+
+// tokenFromSettings returns the token from a token_command, token_file or token, or "" when none of them are set.
+// Setting more than one of them is an error. The provider and each of its cluster blocks are configured with the
+// same three settings.
+func tokenFromSettings(command []interface{}, path string, token string) (string, error) {
+	if err := checkTokenSettings(command, path, token); err != nil {
+		return "", err
+	}
+
+	if len(command) > 0 {
+		args := []string{}
+		for _, arg := range command {
+			s, _ := arg.(string)
+			args = append(args, s)
+		}
+		return tokenFromCommand(args)
+	}
+	if path != "" {
+		return tokenFromFile(path)
+	}
+	return token, nil
+}
+
+// checkTokenSettings returns an error when more than one of token_command, token_file and token are set
+func checkTokenSettings(command []interface{}, path string, token string) error {
+	set := []string{}
+	for name, ok := range map[string]bool{"token": token != "", "token_file": path != "", "token_command": len(command) > 0} {
+		if ok {
+			set = append(set, name)
+		}
+	}
+	if len(set) > 1 {
+		sort.Strings(set)
+		return fmt.Errorf("only one of token, token_file or token_command may be set, got %s", strings.Join(set, " and "))
+	}
+	return nil
+}
+
+func tokenFromFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading token_file. %s", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("token_file %s is empty", path)
+	}
+	return token, nil
+}
+
+// tokenFromCommand runs the command, the first argument being the program, and returns the token it prints
+func tokenFromCommand(args []string) (string, error) {
+	if len(args) == 0 || args[0] == "" {
+		return "", fmt.Errorf("token_command must name a program to run")
+	}
+	key := strings.Join(args, "\x00")
+
+	tokenCommandCache.Lock()
+	defer tokenCommandCache.Unlock()
+	if token, ok := tokenCommandCache.tokens[key]; ok {
+		return token, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("token_command %s did not finish within %s", args[0], tokenCommandTimeout)
+		}
+		return "", fmt.Errorf("error running token_command %s. %s %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("token_command %s did not print a token", args[0])
+	}
+
+	tokenCommandCache.tokens[key] = token
+	return token, nil
+}
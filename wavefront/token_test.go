@@ -0,0 +1,205 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/spaceapegames/go-wavefront"
+)
+
+func TestAccWavefrontAlert_TokenCommand(t *testing.T) {
+	var record wavefront.Alert
+	// The token comes from token_command alone, which may not be set alongside WAVEFRONT_TOKEN
+	token := os.Getenv("WAVEFRONT_TOKEN")
+	defer os.Setenv("WAVEFRONT_TOKEN", token)
+
+	testAccResourceTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			os.Unsetenv("WAVEFRONT_TOKEN")
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckWavefrontAlertDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckWavefrontProvider_tokenCommand(token) + testAccCheckWavefrontAlert_basic(),
+				Check:  testAccCheckWavefrontAlertExists("wavefront_alert.test_alert", &record),
+			},
+		},
+	})
+}
+
+func TestResolveToken(t *testing.T) {
+	defer os.Setenv("WAVEFRONT_TOKEN", os.Getenv("WAVEFRONT_TOKEN"))
+	os.Unsetenv("WAVEFRONT_TOKEN")
+	dir, err := ioutil.TempDir("", "wavefront-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	ioutil.WriteFile(tokenFile, []byte("file-token\n"), 0600)
+
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"token": "config-token"}, "config-token"},
+		{map[string]interface{}{"token_file": tokenFile}, "file-token"},
+		{map[string]interface{}{"token_command": []interface{}{"echo", " command-token "}}, "command-token"},
+	}
+
+	for _, c := range cases {
+		token, err := resolveToken(testProviderData(t, c.raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != c.expected {
+			t.Errorf("expected token %q for %v, got %q", c.expected, c.raw, token)
+		}
+	}
+}
+
+func TestResolveToken_conflict(t *testing.T) {
+	defer os.Setenv("WAVEFRONT_TOKEN", os.Getenv("WAVEFRONT_TOKEN"))
+	os.Unsetenv("WAVEFRONT_TOKEN")
+
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{map[string]interface{}{"token": "config-token", "token_file": "/token"}, "got token and token_file"},
+		{map[string]interface{}{"token_file": "/token", "token_command": []interface{}{"echo", "command-token"}},
+			"got token_command and token_file"},
+		{map[string]interface{}{"token": "config-token", "token_file": "/token", "token_command": []interface{}{"echo"}},
+			"got token and token_command and token_file"},
+	}
+
+	for _, c := range cases {
+		_, err := resolveToken(testProviderData(t, c.raw))
+		if err == nil || !strings.Contains(err.Error(), "only one of token, token_file or token_command may be set, "+c.expected) {
+			t.Errorf("expected a conflict error for %v, got %v", c.raw, err)
+		}
+	}
+
+	// A token from WAVEFRONT_TOKEN conflicts with a token_file too
+	os.Setenv("WAVEFRONT_TOKEN", "env-token")
+	_, err := resolveToken(testProviderData(t, map[string]interface{}{"token_file": "/token"}))
+	if err == nil || !strings.Contains(err.Error(), "may be set by WAVEFRONT_TOKEN and WAVEFRONT_TOKEN_FILE") {
+		t.Errorf("expected a conflict error naming the environment variables, got %v", err)
+	}
+}
+
+func TestResolveToken_errorWithoutHint(t *testing.T) {
+	defer os.Setenv("WAVEFRONT_TOKEN", os.Getenv("WAVEFRONT_TOKEN"))
+	os.Unsetenv("WAVEFRONT_TOKEN")
+
+	// Only a conflict mentions the environment variables, other errors are returned as they are
+	_, err := resolveToken(testProviderData(t, map[string]interface{}{"token_file": "/does/not/exist"}))
+	if err == nil || !strings.HasPrefix(err.Error(), "error reading token_file") ||
+		strings.Contains(err.Error(), "WAVEFRONT_TOKEN") {
+		t.Errorf("expected the token_file error alone, got %v", err)
+	}
+	_, err = resolveToken(testProviderData(t, map[string]interface{}{"token_command": []interface{}{""}}))
+	if err == nil || err.Error() != "token_command must name a program to run" {
+		t.Errorf("expected the token_command error alone, got %v", err)
+	}
+}
+
+func TestResolveToken_notSet(t *testing.T) {
+	defer os.Setenv("WAVEFRONT_TOKEN", os.Getenv("WAVEFRONT_TOKEN"))
+	os.Unsetenv("WAVEFRONT_TOKEN")
+
+	_, err := resolveToken(testProviderData(t, map[string]interface{}{"token": ""}))
+	if err == nil || err.Error() != "one of token, token_file or token_command must be set" {
+		t.Errorf("expected an error when no token is set, got %v", err)
+	}
+}
+
+func TestTokenFromFile_invalid(t *testing.T) {
+	empty, err := ioutil.TempFile("", "wavefront-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty.WriteString("  \n")
+	empty.Close()
+	defer os.Remove(empty.Name())
+
+	if _, err := tokenFromFile(empty.Name()); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("expected an error for an empty token_file, got %v", err)
+	}
+	if _, err := tokenFromFile("/does/not/exist"); err == nil || !strings.Contains(err.Error(), "error reading token_file") {
+		t.Errorf("expected an error for a missing token_file, got %v", err)
+	}
+}
+
+func TestTokenFromCommand_cached(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavefront-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	runs := filepath.Join(dir, "runs")
+
+	args := []string{"sh", "-c", "echo run >> " + runs + "; echo cached-token"}
+	for i := 0; i < 3; i++ {
+		token, err := tokenFromCommand(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "cached-token" {
+			t.Errorf("expected cached-token, got %q", token)
+		}
+	}
+
+	b, _ := ioutil.ReadFile(runs)
+	if n := strings.Count(string(b), "run"); n != 1 {
+		t.Errorf("expected token_command to be run once, got %d", n)
+	}
+}
+
+func TestTokenFromCommand_invalid(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{}, "token_command must name a program to run"},
+		{[]string{"sh", "-c", "echo access denied >&2; exit 1"}, "error running token_command sh. exit status 1 access denied"},
+		{[]string{"sh", "-c", "echo"}, "token_command sh did not print a token"},
+	}
+
+	for _, c := range cases {
+		_, err := tokenFromCommand(c.args)
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("expected error %q for %v, got %v", c.expected, c.args, err)
+		}
+	}
+}
+
+func TestTokenFromCommand_timeout(t *testing.T) {
+	defer func(timeout time.Duration) { tokenCommandTimeout = timeout }(tokenCommandTimeout)
+	tokenCommandTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := tokenFromCommand([]string{"sleep", "10"})
+	if err == nil || err.Error() != "token_command sleep did not finish within 100ms" {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected token_command to be killed after its timeout, it ran for %s", elapsed)
+	}
+}
+
+func testAccCheckWavefrontProvider_tokenCommand(token string) string {
+	return fmt.Sprintf(`
+provider "wavefront" {
+  token_command = ["echo", "%s"]
+}
+`, token)
+}
@@ -0,0 +1,171 @@
+package wavefront_plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/logging"
+)
+
+const redacted = "REDACTED"
+
+var (
+	// Headers whose values are never logged
+	sensitiveHeaders = map[string]bool{
+		"Authorization":       true,
+		"Proxy-Authorization": true,
+		"Cookie":              true,
+		"Set-Cookie":          true,
+	}
+	// Fields of JSON and form bodies whose values are never logged. The values of every header in the
+	// customHttpHeaders of an alert target are redacted, as they often hold webhook credentials.
+	sensitiveFields = map[string]bool{
+		"customHttpHeaders": true,
+		"access_token":      true,
+		"refresh_token":     true,
+		"client_secret":     true,
+		"token":             true,
+	}
+)
+
+// traceTransport logs each request sent to Wavefront, and to the token endpoint, through the Terraform log. With
+// TF_LOG=DEBUG the method, path, status and latency are logged, with TF_LOG=TRACE the headers and bodies are too.
+// Credentials are redacted from the headers and bodies.
+type traceTransport struct {
+	next http.RoundTripper
+	// Whether headers and bodies are logged
+	trace bool
+}
+
+func newTraceTransport(next http.RoundTripper) http.RoundTripper {
+	level := logging.LogLevel()
+	if level != "DEBUG" && level != "TRACE" {
+		return next
+	}
+	return &traceTransport{next: next, trace: level == "TRACE"}
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.trace {
+		var body []byte
+		if req.Body != nil {
+			var err error
+			body, err = ioutil.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		log.Printf("[TRACE] Wavefront API request %s %s\n%s", req.Method, requestPath(req),
+			traceMessage(req.Header, body))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		log.Printf("[DEBUG] Wavefront API %s %s failed after %s: %s", req.Method, req.URL.Path, latency, err)
+		return resp, err
+	}
+	log.Printf("[DEBUG] Wavefront API %s %s returned %s in %s", req.Method, req.URL.Path, resp.Status, latency)
+
+	if t.trace {
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		log.Printf("[TRACE] Wavefront API response to %s %s: %s\n%s", req.Method, req.URL.Path, resp.Status,
+			traceMessage(resp.Header, body))
+	}
+	return resp, nil
+}
+
+// requestPath returns the path and query of a request, the query parameters of Wavefront's API holding no credentials
+func requestPath(req *http.Request) string {
+	if req.URL.RawQuery == "" {
+		return req.URL.Path
+	}
+	return req.URL.Path + "?" + req.URL.RawQuery
+}
+
+// traceMessage formats redacted headers and a body for the log
+func traceMessage(header http.Header, body []byte) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		for _, v := range header[name] {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				v = redacted
+			}
+			fmt.Fprintf(&b, "%s: %s\n", name, v)
+		}
+	}
+	if len(body) > 0 {
+		b.WriteString("\n")
+		b.WriteString(redactBody(body, header.Get("Content-Type")))
+	}
+	return b.String()
+}
+
+// redactBody returns a body with the values of sensitiveFields redacted. Bodies which are neither JSON nor a form
+// are returned as they are.
+func redactBody(body []byte, contentType string) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		out, _ := json.MarshalIndent(redactJSON(v), "", "  ")
+		return string(out)
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for k := range form {
+				if sensitiveFields[k] {
+					form.Set(k, redacted)
+				}
+			}
+			return form.Encode()
+		}
+	}
+	return string(body)
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if !sensitiveFields[k] {
+				v[k] = redactJSON(field)
+				continue
+			}
+			if headers, ok := field.(map[string]interface{}); ok {
+				// Keep the names of custom headers, which are useful when debugging
+				for name := range headers {
+					headers[name] = redacted
+				}
+			} else if field != nil {
+				v[k] = redacted
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+	return v
+}
@@ -0,0 +1,127 @@
+package wavefront_plugin
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureLog returns the output logged while f runs
+func captureLog(f func()) string {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+	f()
+	return buf.String()
+}
+
+func TestTraceTransport(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":{"result":"OK"},"response":` + string(body) + `}`))
+	}))
+	defer s.Close()
+
+	client := &http.Client{Transport: &traceTransport{next: http.DefaultTransport, trace: true}}
+	var resp *http.Response
+	output := captureLog(func() {
+		req, _ := http.NewRequest(http.MethodPost, s.URL+"/api/v2/notificant",
+			strings.NewReader(`{"title":"hook","customHttpHeaders":{"X-Api-Key":"webhook-secret"}}`))
+		req.Header.Set("Authorization", "Bearer api-token")
+		var err error
+		resp, err = client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// The response body can still be read by go-wavefront
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "webhook-secret") {
+		t.Errorf("expected the response body to be passed on, got %s", body)
+	}
+
+	for _, expected := range []string{
+		"[TRACE] Wavefront API request POST /api/v2/notificant",
+		"Authorization: REDACTED",
+		`"X-Api-Key": "REDACTED"`,
+		`"title": "hook"`,
+		"[DEBUG] Wavefront API POST /api/v2/notificant returned 200 OK in ",
+		"[TRACE] Wavefront API response to POST /api/v2/notificant: 200 OK",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected the log to contain %q, got\n%s", expected, output)
+		}
+	}
+	for _, secret := range []string{"api-token", "webhook-secret"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("expected %s to be redacted, got\n%s", secret, output)
+		}
+	}
+}
+
+func TestTraceTransport_debug(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	client := &http.Client{Transport: &traceTransport{next: http.DefaultTransport}}
+	output := captureLog(func() {
+		resp, err := client.Get(s.URL + "/api/v2/alert/123")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	})
+
+	if !strings.Contains(output, "[DEBUG] Wavefront API GET /api/v2/alert/123 returned 404 Not Found in ") {
+		t.Errorf("expected the request to be logged, got\n%s", output)
+	}
+	if strings.Contains(output, "[TRACE]") {
+		t.Errorf("expected no headers or bodies to be logged at DEBUG, got\n%s", output)
+	}
+}
+
+func TestNewTraceTransport(t *testing.T) {
+	defer os.Setenv("TF_LOG", os.Getenv("TF_LOG"))
+
+	os.Setenv("TF_LOG", "")
+	if _, ok := newTraceTransport(http.DefaultTransport).(*traceTransport); ok {
+		t.Errorf("expected no tracing without TF_LOG")
+	}
+	os.Setenv("TF_LOG", "INFO")
+	if _, ok := newTraceTransport(http.DefaultTransport).(*traceTransport); ok {
+		t.Errorf("expected no tracing with TF_LOG=INFO")
+	}
+	os.Setenv("TF_LOG", "trace")
+	if tr, ok := newTraceTransport(http.DefaultTransport).(*traceTransport); !ok || !tr.trace {
+		t.Errorf("expected bodies to be traced with TF_LOG=trace")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	cases := []struct {
+		body        string
+		contentType string
+		expected    string
+	}{
+		{`{"access_token":"abc","expires_in":300}`, "application/json", "{\n  \"access_token\": \"REDACTED\",\n  \"expires_in\": 300\n}"},
+		{`[{"token":"abc"}]`, "application/json", "[\n  {\n    \"token\": \"REDACTED\"\n  }\n]"},
+		{"grant_type=refresh_token&refresh_token=abc", "application/x-www-form-urlencoded", "grant_type=refresh_token&refresh_token=REDACTED"},
+		{"not json", "text/plain", "not json"},
+	}
+
+	for _, c := range cases {
+		if redacted := redactBody([]byte(c.body), c.contentType); redacted != c.expected {
+			t.Errorf("expected %s to be redacted as %q, got %q", c.body, c.expected, redacted)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package wavefront_plugin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/spaceapegames/terraform-provider-wavefront/wql"
+)
+
+// validateStringIn returns a ValidateFunc which checks that a string attribute is one of the valid values
+func validateStringIn(valid ...string) schema.SchemaValidateFunc {
+	return func(val interface{}, key string) ([]string, []error) {
+		v := val.(string)
+		for _, s := range valid {
+			if v == s {
+				return nil, nil
+			}
+		}
+		return nil, []error{fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(valid, ", "), v)}
+	}
+}
+
+// validateIntAtLeast returns a ValidateFunc which checks that an int attribute is at least min
+func validateIntAtLeast(min int) schema.SchemaValidateFunc {
+	return func(val interface{}, key string) ([]string, []error) {
+		if v := val.(int); v < min {
+			return nil, []error{fmt.Errorf("%s must be at least %d, got %d", key, min, v)}
+		}
+		return nil, nil
+	}
+}
+
+// validateIntBetween returns a ValidateFunc which checks that an int attribute is between min and max inclusive
+func validateIntBetween(min int, max int) schema.SchemaValidateFunc {
+	return func(val interface{}, key string) ([]string, []error) {
+		if v := val.(int); v < min || v > max {
+			return nil, []error{fmt.Errorf("%s must be between %d and %d, got %d", key, min, max, v)}
+		}
+		return nil, nil
+	}
+}
+
+// validateFloatAtLeast returns a ValidateFunc which checks that a float attribute is at least min
+func validateFloatAtLeast(min float64) schema.SchemaValidateFunc {
+	return func(val interface{}, key string) ([]string, []error) {
+		if v := val.(float64); v < min {
+			return nil, []error{fmt.Errorf("%s must be at least %g, got %g", key, min, v)}
+		}
+		return nil, nil
+	}
+}
+
+// validateQuery is a ValidateFunc which checks that a string attribute is a valid Wavefront query. Empty values are
+// left to the attribute's Required or Optional setting. Calls to unknown functions which aren't close to a known one
+// are warnings, so that functions added to Wavefront since the wql package can still be used.
+func validateQuery(val interface{}, key string) ([]string, []error) {
+	v := val.(string)
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+	q, err := wql.Parse(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid Wavefront query: %s", key, err)}
+	}
+	return queryWarnings(key, q), nil
+}
+
+func queryWarnings(key string, q *wql.Query) []string {
+	var warnings []string
+	for _, w := range q.Warnings {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", key, w))
+	}
+	return warnings
+}
+
+// validateEventQuery is a ValidateFunc which checks that a string attribute is a Wavefront events() query
+func validateEventQuery(val interface{}, key string) ([]string, []error) {
+	v := val.(string)
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+	q, err := wql.ParseEvents(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid Wavefront events query: %s", key, err)}
+	}
+	return queryWarnings(key, q), nil
+}
+
+var timeWindowPattern = regexp.MustCompile(`^[1-9][0-9]*[smhdw]$`)
+
+// validateTimeWindow is a ValidateFunc which checks that a string attribute is a Wavefront time window such as 30m,
+// 2h or 7d
+func validateTimeWindow(val interface{}, key string) ([]string, []error) {
+	v := val.(string)
+	if v != "" && !timeWindowPattern.MatchString(v) {
+		return nil, []error{fmt.Errorf("%s must be a number of s, m, h, d or w such as 2h, got %q", key, v)}
+	}
+	return nil, nil
+}
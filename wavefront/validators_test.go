@@ -0,0 +1,73 @@
+package wavefront_plugin
+
+import (
+	"testing"
+)
+
+func TestValidateStringIn(t *testing.T) {
+	validate := validateStringIn("INFO", "WARN")
+
+	if _, errs := validate("WARN", "severity"); len(errs) != 0 {
+		t.Errorf("expected WARN to be valid, got %v", errs)
+	}
+
+	_, errs := validate("warn", "severity")
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(errs))
+	}
+	if errs[0].Error() != `severity must be one of INFO, WARN, got "warn"` {
+		t.Errorf("unexpected error %s", errs[0])
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	for _, v := range []string{"", "  ", "ts(cpu.load) > 80", "sum(ts(cpu.load, env=${env}), sources)"} {
+		if _, errs := validateQuery(v, "condition"); len(errs) != 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+
+	warnings, errs := validateQuery("newFunction(ts(cpu.load)[5m]) > 80", "condition")
+	if len(errs) != 0 {
+		t.Errorf("expected an unknown function not to be an error, got %v", errs)
+	}
+	expectedWarning := `condition: line 1, column 1: unknown function "newFunction"`
+	if len(warnings) != 1 || warnings[0] != expectedWarning {
+		t.Errorf("expected warning '%s', got %q", expectedWarning, warnings)
+	}
+
+	_, errs = validateQuery("sum(ts(cpu.load) > 80", "condition")
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(errs))
+	}
+	expected := `condition is not a valid Wavefront query: line 1, column 22: missing ")" to close the "(" at line 1, column 4`
+	if errs[0].Error() != expected {
+		t.Errorf("expected error '%s', got '%s'", expected, errs[0])
+	}
+}
+
+func TestValidateEventQuery(t *testing.T) {
+	for _, v := range []string{"", `events(name="deploy")`, `events(type=alert, severity=SEVERE)`} {
+		if _, errs := validateEventQuery(v, "event_query"); len(errs) != 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range []string{"ts(cpu.load)", "events(name=", "deploy"} {
+		if _, errs := validateEventQuery(v, "event_query"); len(errs) != 1 {
+			t.Errorf("expected %q to be invalid, got %v", v, errs)
+		}
+	}
+}
+
+func TestValidateTimeWindow(t *testing.T) {
+	for _, v := range []string{"", "30s", "15m", "2h", "7d", "1w"} {
+		if _, errs := validateTimeWindow(v, "default_time_window"); len(errs) != 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+	for _, v := range []string{"2", "h", "0h", "2 hours", "-1d", "2H"} {
+		if _, errs := validateTimeWindow(v, "default_time_window"); len(errs) != 1 {
+			t.Errorf("expected %q to be invalid, got %v", v, errs)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package wql
+
+// Node is an element of a parsed query
+type Node interface {
+	// Pos is the byte offset of the node in the query
+	Pos() int
+}
+
+// Call is a function call such as ts(cpu.load) or mavg(5m, ts(cpu.load))
+type Call struct {
+	Name string
+	Args []Node
+	pos  int
+}
+
+// BinaryExpr is an arithmetic, comparison or logical operation such as ts(a) + ts(b) or ts(a) > 80
+type BinaryExpr struct {
+	Op   string
+	X, Y Node
+	pos  int
+}
+
+// UnaryExpr is a negation such as -ts(a) or not ts(a) > 80
+type UnaryExpr struct {
+	Op  string
+	X   Node
+	pos int
+}
+
+// Literal is a number, duration, string or bare word such as an aggregation grouping or metric name
+type Literal struct {
+	Kind  LiteralKind
+	Value string
+	pos   int
+}
+
+// Window is an expression over a time window such as ts(cpu.load)[5m]
+type Window struct {
+	X        Node
+	Duration *Literal
+	pos      int
+}
+
+// Alias is an expression named with as, such as ts(cpu.load) as cpu
+type Alias struct {
+	X    Node
+	Name string
+	pos  int
+}
+
+// Filter is a key=value or key!=value term in the filters of a data function such as ts()
+type Filter struct {
+	Key   string
+	Op    string
+	Value *Literal
+	pos   int
+}
+
+type LiteralKind int
+
+const (
+	Number LiteralKind = iota
+	Duration
+	String
+	Word
+)
+
+func (n *Call) Pos() int       { return n.pos }
+func (n *BinaryExpr) Pos() int { return n.pos }
+func (n *UnaryExpr) Pos() int  { return n.pos }
+func (n *Literal) Pos() int    { return n.pos }
+func (n *Window) Pos() int     { return n.pos }
+func (n *Alias) Pos() int      { return n.pos }
+func (n *Filter) Pos() int     { return n.pos }
@@ -0,0 +1,39 @@
+package wql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyntaxError describes where and why a query failed to parse. Line and Column start at 1.
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Offset  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+func syntaxErrorf(input string, offset int, format string, args ...interface{}) *SyntaxError {
+	line, column := position(input, offset)
+	return &SyntaxError{
+		Line:    line,
+		Column:  column,
+		Offset:  offset,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// position converts a byte offset into a line and column
+func position(input string, offset int) (int, int) {
+	if offset > len(input) {
+		offset = len(input)
+	}
+	before := input[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := offset - strings.LastIndex(before, "\n")
+	return line, column
+}
@@ -0,0 +1,119 @@
+package wql
+
+import "strings"
+
+// The data functions, whose arguments are a metric, histogram or event selector followed by filters rather than
+// expressions
+var dataFunctions = map[string]bool{
+	"ts":     true,
+	"hs":     true,
+	"events": true,
+	"spans":  true,
+	"traces": true,
+}
+
+// Functions whose first argument is an expression and whose remaining arguments are filters
+var filterFunctions = map[string]bool{
+	"retainseries": true,
+	"removeseries": true,
+	"filter":       true,
+}
+
+// The functions of the Wavefront Query Language keyed by their lower case name, as function names are case
+// insensitive
+var functions = map[string]string{}
+
+func init() {
+	for _, name := range []string{
+		// Data
+		"ts", "hs", "events", "spans", "traces",
+		// Aggregation
+		"sum", "avg", "min", "max", "count", "variance", "percentile", "median",
+		"rawsum", "rawavg", "rawmin", "rawmax", "rawcount", "rawvariance", "rawpercentile", "globalCount",
+		// Filtering and ranking
+		"highpoints", "lowpoints", "top", "bottom", "topk", "bottomk", "filter", "retainSeries", "removeSeries",
+		"sample", "limit", "hideBefore", "hideAfter", "between", "nonzero",
+		// Conditional
+		"if", "default", "exists",
+		// Moving window
+		"mavg", "msum", "mmin", "mmax", "mcount", "mmedian", "mpercentile", "mvar", "mcorr", "mdiff",
+		"mseriescount", "flapping", "any", "all",
+		// Time and rate
+		"rate", "deriv", "ratediff", "lag", "integral", "integrate", "at", "time", "timestamp",
+		"year", "month", "dayOfYear", "day", "weekday", "hour", "minute", "timeFmt",
+		// Math
+		"abs", "ceil", "floor", "round", "trunc", "sign", "sqrt", "pow", "exp", "log", "log10",
+		"sin", "cos", "tan", "asin", "acos", "atan", "atan2", "sinh", "cosh", "tanh",
+		"toDegrees", "toRadians", "random", "normalize", "haversine", "bestEffort",
+		// Missing data and interpolation
+		"last", "next", "interpolate", "missing", "align",
+		// Smoothing
+		"lowpass", "highpass",
+		// Metadata
+		"taggify", "aliasSource", "aliasMetric", "aliasTag", "collect",
+		// Prediction
+		"hw", "nnforecast", "anomalous",
+		// Histograms
+		"cumulativePercentile", "merge", "alignedSummary", "summary",
+		// Events
+		"closed", "ongoing", "firstEvent", "lastEvent", "since", "until", "timespan", "after", "before",
+		"union", "intersect",
+		// Traces and spans
+		"highestDuration", "lowestDuration", "leafOnly", "rootOnly", "childOf", "parentOf",
+	} {
+		functions[strings.ToLower(name)] = name
+	}
+}
+
+// suggestFunction returns the known function closest to a misspelled name, or "" if none are close. Ties are
+// broken by the longest shared prefix and then alphabetically.
+func suggestFunction(name string) string {
+	best, bestDistance, bestPrefix := "", 3, 0
+	for lower, candidate := range functions {
+		distance := levenshtein(name, lower)
+		prefix := commonPrefix(name, lower)
+		if distance < bestDistance ||
+			(distance == bestDistance && (prefix > bestPrefix || (prefix == bestPrefix && candidate < best))) {
+			best, bestDistance, bestPrefix = candidate, distance, prefix
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,272 @@
+package wql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// 42, 1.5, 2e3
+	tokNumber
+	// A number with a unit, such as 5m or 1h
+	tokDuration
+	// "cpu.usage" or 'cpu.usage'
+	tokString
+	// Identifiers, keywords, metric names and bare filter values. May contain ${param} substitutions
+	tokWord
+	tokLParen
+	tokRParen
+	tokComma
+	tokLBracket
+	tokRBracket
+	tokOperator
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokEOF:
+		return "end of query"
+	case tokNumber:
+		return "number"
+	case tokDuration:
+		return "duration"
+	case tokString:
+		return "string"
+	case tokWord:
+		return "word"
+	case tokLParen:
+		return `"("`
+	case tokRParen:
+		return `")"`
+	case tokComma:
+		return `","`
+	case tokLBracket:
+		return `"["`
+	case tokRBracket:
+		return `"]"`
+	default:
+		return "operator"
+	}
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	// Byte offsets of the token in the query
+	start int
+	end   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF, tokLParen, tokRParen, tokComma, tokLBracket, tokRBracket:
+		return t.kind.String()
+	case tokString:
+		return t.text
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// isKeyword reports whether the token is one of the keywords and, or, not and as
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, keyword)
+}
+
+// lexMode controls how bare words are scanned. Inside the filters of ts(), hs(), events() and friends a bare
+// word can contain characters such as '-', '*' and '/' which are operators elsewhere.
+type lexMode int
+
+const (
+	modeExpression lexMode = iota
+	modeFilter
+)
+
+// The operators recognised in expressions, longest first
+var expressionOperators = []string{"==", "!=", "<>", "<=", ">=", "<", ">", "=", "+", "-", "*", "/", "%", "^"}
+
+// scan returns the token starting at or after offset
+func scan(input string, offset int, mode lexMode) (token, error) {
+	for offset < len(input) && isSpace(input[offset]) {
+		offset++
+	}
+	if offset >= len(input) {
+		return token{kind: tokEOF, start: offset, end: offset}, nil
+	}
+
+	c := input[offset]
+	switch {
+	case c == '(':
+		return token{kind: tokLParen, text: "(", start: offset, end: offset + 1}, nil
+	case c == ')':
+		return token{kind: tokRParen, text: ")", start: offset, end: offset + 1}, nil
+	case c == ',':
+		return token{kind: tokComma, text: ",", start: offset, end: offset + 1}, nil
+	case c == '"' || c == '\'':
+		return scanString(input, offset)
+	}
+
+	if mode == modeFilter {
+		if strings.HasPrefix(input[offset:], "!=") {
+			return token{kind: tokOperator, text: "!=", start: offset, end: offset + 2}, nil
+		}
+		if c == '=' {
+			return token{kind: tokOperator, text: "=", start: offset, end: offset + 1}, nil
+		}
+		return scanWord(input, offset, isFilterWordChar)
+	}
+
+	// Time windows such as [5m] only follow expressions, in filters brackets are part of a value
+	if c == '[' {
+		return token{kind: tokLBracket, text: "[", start: offset, end: offset + 1}, nil
+	}
+	if c == ']' {
+		return token{kind: tokRBracket, text: "]", start: offset, end: offset + 1}, nil
+	}
+	if isDigit(c) || (c == '.' && offset+1 < len(input) && isDigit(input[offset+1])) {
+		return scanNumber(input, offset)
+	}
+	if isWordStart(c) {
+		return scanWord(input, offset, isWordChar)
+	}
+	for _, op := range expressionOperators {
+		if strings.HasPrefix(input[offset:], op) {
+			return token{kind: tokOperator, text: op, start: offset, end: offset + len(op)}, nil
+		}
+	}
+	return token{}, syntaxErrorf(input, offset, "unexpected character %q", rune(c))
+}
+
+func scanString(input string, start int) (token, error) {
+	quote := input[start]
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case quote:
+			return token{kind: tokString, text: input[start : i+1], start: start, end: i + 1}, nil
+		}
+	}
+	return token{}, syntaxErrorf(input, start, "unterminated string")
+}
+
+func scanNumber(input string, start int) (token, error) {
+	i := start
+	for i < len(input) && isDigit(input[i]) {
+		i++
+	}
+	if i < len(input) && input[i] == '.' {
+		i++
+		for i < len(input) && isDigit(input[i]) {
+			i++
+		}
+	}
+	// An exponent, as long as it is followed by digits; otherwise the 'e' is a unit
+	if i+1 < len(input) && (input[i] == 'e' || input[i] == 'E') {
+		j := i + 1
+		if input[j] == '+' || input[j] == '-' {
+			j++
+		}
+		if j < len(input) && isDigit(input[j]) {
+			i = j
+			for i < len(input) && isDigit(input[i]) {
+				i++
+			}
+		}
+	}
+
+	kind := tokNumber
+	if i < len(input) && isLetter(input[i]) {
+		kind = tokDuration
+		for i < len(input) && isLetter(input[i]) {
+			i++
+		}
+	}
+	if i < len(input) && isWordChar(input[i]) {
+		return token{}, syntaxErrorf(input, start, "invalid number %q", input[start:i+1])
+	}
+	return token{kind: kind, text: input[start:i], start: start, end: i}, nil
+}
+
+// scanWord scans a run of word characters and ${param} substitutions
+func scanWord(input string, start int, wordChar func(byte) bool) (token, error) {
+	i := start
+	for i < len(input) {
+		if strings.HasPrefix(input[i:], "${") {
+			end := strings.IndexByte(input[i:], '}')
+			if end < 0 {
+				return token{}, syntaxErrorf(input, i, "unterminated parameter, expected \"}\"")
+			}
+			if !isParamName(input[i+2 : i+end]) {
+				return token{}, syntaxErrorf(input, i, "invalid parameter name %q", input[i+2:i+end])
+			}
+			i += end + 1
+			continue
+		}
+		if !wordChar(input[i]) {
+			break
+		}
+		i++
+	}
+	if i == start {
+		return token{}, syntaxErrorf(input, start, "unexpected character %q", rune(input[start]))
+	}
+	return token{kind: tokWord, text: input[start:i], start: start, end: i}, nil
+}
+
+// params returns the names of the ${param} substitutions in a word or string
+func params(text string) []string {
+	var names []string
+	for {
+		start := strings.Index(text, "${")
+		if start < 0 {
+			return names
+		}
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			return names
+		}
+		names = append(names, text[start+2:start+end])
+		text = text[start+end+1:]
+	}
+}
+
+func isParamName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isWordStart(c byte) bool {
+	return isLetter(c) || c == '_' || c == '~' || c == '$'
+}
+
+func isWordChar(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '.'
+}
+
+func isFilterWordChar(c byte) bool {
+	return !isSpace(c) && c != '(' && c != ')' && c != ',' && c != '=' && c != '!' && c != '"' && c != '\''
+}
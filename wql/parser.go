@@ -0,0 +1,462 @@
+// Package wql parses queries written in the Wavefront Query Language, such as
+//
+//	sum(mavg(5m, ts("cpu.usage", env=${env} and not source="test-*")), sources) > 80
+//
+// It checks the syntax of a query, but not the number or types of the arguments passed to its functions. A call to a
+// function it doesn't know is an error when the name is close to a known function, as it is most likely misspelled,
+// and otherwise a warning, as Wavefront adds functions faster than this list.
+package wql
+
+import (
+	"sort"
+	"strings"
+)
+
+// Query is a parsed query
+type Query struct {
+	Root Node
+	// The names of the ${param} substitutions used by the query, sorted and without duplicates
+	Params []string
+	// Problems which don't stop the query from parsing, such as calls to unknown functions, with their line and column
+	Warnings []string
+}
+
+// Parse parses a query, returning a *SyntaxError if it is invalid
+func Parse(query string) (*Query, error) {
+	p := &parser{input: query, params: map[string]bool{}}
+
+	if strings.TrimSpace(query) == "" {
+		return nil, syntaxErrorf(query, 0, "empty query")
+	}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	tok, err := p.peek(modeExpression)
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind == tokRParen {
+		return nil, syntaxErrorf(query, tok.start, `unexpected ")" without a matching "("`)
+	}
+	if tok.kind != tokEOF {
+		return nil, syntaxErrorf(query, tok.start, "unexpected %s after the end of the expression", tok)
+	}
+
+	q := &Query{Root: root, Params: []string{}, Warnings: p.warnings}
+	for name := range p.params {
+		q.Params = append(q.Params, name)
+	}
+	sort.Strings(q.Params)
+	return q, nil
+}
+
+// ParseEvents parses a query that must be an events() expression, as used by a dashboard's event query
+func ParseEvents(query string) (*Query, error) {
+	q, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	if call, ok := q.Root.(*Call); !ok || !strings.EqualFold(call.Name, "events") {
+		return nil, syntaxErrorf(query, q.Root.Pos(), "expected an events() expression")
+	}
+	return q, nil
+}
+
+type parser struct {
+	input    string
+	offset   int
+	params   map[string]bool
+	warnings []string
+}
+
+func (p *parser) peek(mode lexMode) (token, error) {
+	return scan(p.input, p.offset, mode)
+}
+
+func (p *parser) next(mode lexMode) (token, error) {
+	tok, err := scan(p.input, p.offset, mode)
+	if err != nil {
+		return tok, err
+	}
+	p.offset = tok.end
+	return tok, nil
+}
+
+// keyword consumes the next token if it is the given keyword
+func (p *parser) keyword(mode lexMode, keyword string) (token, bool, error) {
+	tok, err := p.peek(mode)
+	if err != nil {
+		return tok, false, err
+	}
+	if !tok.isKeyword(keyword) {
+		return tok, false, nil
+	}
+	p.offset = tok.end
+	return tok, true, nil
+}
+
+func (p *parser) literal(tok token) *Literal {
+	kind := Word
+	switch tok.kind {
+	case tokNumber:
+		kind = Number
+	case tokDuration:
+		kind = Duration
+	case tokString:
+		kind = String
+	}
+	for _, name := range params(tok.text) {
+		p.params[name] = true
+	}
+	return &Literal{Kind: kind, Value: tok.text, pos: tok.start}
+}
+
+// parseExpr parses an expression, which may be named with as, such as ts(cpu.load) as cpu
+func (p *parser) parseExpr() (Node, error) {
+	x, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	as, ok, err := p.keyword(modeExpression, "as")
+	if err != nil || !ok {
+		return x, err
+	}
+	name, err := p.next(modeExpression)
+	if err != nil {
+		return nil, err
+	}
+	if name.kind != tokWord || isKeyword(name) {
+		return nil, syntaxErrorf(p.input, name.start, `unexpected %s, expected a name after "as"`, name)
+	}
+	return &Alias{X: x, Name: name.text, pos: as.start}, nil
+}
+
+// isKeyword reports whether a token is one of the keywords, which can't be used as a name or an expression
+func isKeyword(tok token) bool {
+	for _, keyword := range []string{"and", "or", "not", "as"} {
+		if tok.isKeyword(keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// The binary operators by precedence, lowest first
+var precedence = [][]string{
+	{"or"},
+	{"and"},
+	{"=", "==", "!=", "<>", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+	{"^"},
+}
+
+func (p *parser) parseBinary(level int) (Node, error) {
+	if level == len(precedence) {
+		return p.parseUnary()
+	}
+	if level == 2 {
+		// 'not' binds tighter than 'and' and 'or' but looser than comparisons
+		if tok, ok, err := p.keyword(modeExpression, "not"); err != nil {
+			return nil, err
+		} else if ok {
+			x, err := p.parseBinary(level)
+			if err != nil {
+				return nil, err
+			}
+			return &UnaryExpr{Op: "not", X: x, pos: tok.start}, nil
+		}
+	}
+
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, err := p.peek(modeExpression)
+		if err != nil {
+			return nil, err
+		}
+		op := ""
+		for _, candidate := range precedence[level] {
+			if (tok.kind == tokOperator && tok.text == candidate) || tok.isKeyword(candidate) {
+				op = candidate
+			}
+		}
+		if op == "" {
+			return x, nil
+		}
+		p.offset = tok.end
+
+		// ^ is right associative, so 2 ^ 3 ^ 2 is 2 ^ (3 ^ 2)
+		next := level + 1
+		if op == "^" {
+			next = level
+		}
+		y, err := p.parseBinary(next)
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: op, X: x, Y: y, pos: tok.start}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	tok, err := p.peek(modeExpression)
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind == tokOperator && (tok.text == "-" || tok.text == "+") {
+		p.offset = tok.end
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Op: tok.text, X: x, pos: tok.start}, nil
+	}
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return p.parseWindow(x)
+}
+
+// parseWindow parses the time window which may follow an expression, such as the [5m] of ts(cpu.load)[5m]
+func (p *parser) parseWindow(x Node) (Node, error) {
+	open, err := p.peek(modeExpression)
+	if err != nil || open.kind != tokLBracket {
+		return x, err
+	}
+	p.offset = open.end
+
+	tok, err := p.next(modeExpression)
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokDuration && !(tok.kind == tokWord && len(params(tok.text)) > 0) {
+		return nil, syntaxErrorf(p.input, tok.start, "unexpected %s, expected a time window such as 5m", tok)
+	}
+	window := &Window{X: x, Duration: p.literal(tok), pos: open.start}
+
+	tok, err = p.next(modeExpression)
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokRBracket {
+		line, column := position(p.input, open.start)
+		return nil, syntaxErrorf(p.input, tok.start, `unexpected %s, expected "]" to close the "[" at line %d, column %d`, tok, line, column)
+	}
+	return window, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok, err := p.next(modeExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.kind {
+	case tokNumber, tokDuration, tokString:
+		return p.literal(tok), nil
+	case tokLParen:
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.closeParen(tok); err != nil {
+			return nil, err
+		}
+		return x, nil
+	case tokWord:
+		if isKeyword(tok) {
+			return nil, syntaxErrorf(p.input, tok.start, "unexpected %q, expected an expression", tok.text)
+		}
+		next, err := p.peek(modeExpression)
+		if err != nil {
+			return nil, err
+		}
+		if next.kind == tokLParen {
+			return p.parseCall(tok)
+		}
+		return p.literal(tok), nil
+	case tokEOF:
+		return nil, syntaxErrorf(p.input, tok.start, "unexpected end of query, expected an expression")
+	default:
+		return nil, syntaxErrorf(p.input, tok.start, "unexpected %s, expected an expression", tok)
+	}
+}
+
+// closeParen consumes the ")" matching open
+func (p *parser) closeParen(open token) error {
+	tok, err := p.next(modeExpression)
+	if err != nil {
+		return err
+	}
+	if tok.kind == tokRParen {
+		return nil
+	}
+	line, column := position(p.input, open.start)
+	if tok.kind == tokEOF {
+		return syntaxErrorf(p.input, tok.start, `missing ")" to close the "(" at line %d, column %d`, line, column)
+	}
+	return syntaxErrorf(p.input, tok.start, `unexpected %s, expected ")" to close the "(" at line %d, column %d`, tok, line, column)
+}
+
+func (p *parser) parseCall(name token) (Node, error) {
+	fn := strings.ToLower(name.text)
+	if _, ok := functions[fn]; !ok && len(params(name.text)) == 0 {
+		if suggestion := suggestFunction(fn); suggestion != "" {
+			return nil, syntaxErrorf(p.input, name.start, "unknown function %q, did you mean %q?", name.text, suggestion)
+		}
+		p.warnings = append(p.warnings, syntaxErrorf(p.input, name.start, "unknown function %q", name.text).Error())
+	}
+
+	open, _ := p.next(modeExpression)
+	call := &Call{Name: name.text, pos: name.start}
+
+	mode := modeExpression
+	if dataFunctions[fn] {
+		mode = modeFilter
+	}
+	tok, err := p.peek(mode)
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind == tokRParen {
+		p.offset = tok.end
+		return call, nil
+	}
+
+	for i := 0; ; i++ {
+		var arg Node
+		if dataFunctions[fn] || (filterFunctions[fn] && i > 0) {
+			arg, err = p.parseFilterExpr()
+		} else {
+			arg, err = p.parseExpr()
+		}
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+
+		tok, err := p.peek(modeExpression)
+		if err != nil {
+			return nil, err
+		}
+		if tok.kind != tokComma {
+			break
+		}
+		p.offset = tok.end
+	}
+
+	if err := p.closeParen(open); err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// parseFilterExpr parses the selector and filters of a data function, such as
+// "cpu.usage" or env=prod and not (source=app-1 or source=app-2)
+func (p *parser) parseFilterExpr() (Node, error) {
+	x, err := p.parseFilterAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok, err := p.keyword(modeFilter, "or")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseFilterAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: "or", X: x, Y: y, pos: tok.start}
+	}
+}
+
+func (p *parser) parseFilterAnd() (Node, error) {
+	x, err := p.parseFilterNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok, ok, err := p.keyword(modeFilter, "and")
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseFilterNot()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: "and", X: x, Y: y, pos: tok.start}
+	}
+}
+
+func (p *parser) parseFilterNot() (Node, error) {
+	tok, ok, err := p.keyword(modeFilter, "not")
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		x, err := p.parseFilterNot()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Op: "not", X: x, pos: tok.start}, nil
+	}
+	return p.parseFilterTerm()
+}
+
+func (p *parser) parseFilterTerm() (Node, error) {
+	tok, err := p.next(modeFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.kind {
+	case tokLParen:
+		x, err := p.parseFilterExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.closeParen(tok); err != nil {
+			return nil, err
+		}
+		return x, nil
+	case tokWord, tokString:
+		if tok.isKeyword("and") || tok.isKeyword("or") {
+			return nil, syntaxErrorf(p.input, tok.start, "unexpected %q, expected a filter", tok.text)
+		}
+	case tokEOF:
+		return nil, syntaxErrorf(p.input, tok.start, "unexpected end of query, expected a filter")
+	default:
+		return nil, syntaxErrorf(p.input, tok.start, "unexpected %s, expected a filter", tok)
+	}
+
+	key := p.literal(tok)
+	op, err := p.peek(modeFilter)
+	if err != nil {
+		return nil, err
+	}
+	if op.kind != tokOperator {
+		return key, nil
+	}
+	p.offset = op.end
+
+	value, err := p.next(modeFilter)
+	if err != nil {
+		return nil, err
+	}
+	if value.kind != tokWord && value.kind != tokString {
+		return nil, syntaxErrorf(p.input, value.start, "unexpected %s, expected a value for %s", value, key.Value)
+	}
+	return &Filter{Key: key.Value, Op: op.text, Value: p.literal(value), pos: tok.start}, nil
+}
@@ -0,0 +1,194 @@
+package wql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_valid(t *testing.T) {
+	cases := []string{
+		"42",
+		"-1.5e3",
+		"ts()",
+		`ts("cpu.usage_idle")`,
+		`100-ts("cpu.usage_idle", environment=preprod and cpu=cpu-total ) > 80`,
+		"ts(~sample.cpu.loadavg.1m, source=app-* and not (env=dev or env=test))",
+		`ts(cpu.load, source!="test-1" and tag=prod)`,
+		"ts(cpu.load, app-12)",
+		`ts("cpu.a" or "cpu.b")`,
+		"sum(mavg(5m, ts(cpu.load)), sources, env) >= 0.9",
+		"SUM(rate(ts(requests)), sources)",
+		"align(1m, mean, ts(cpu.load))",
+		`if(ts(a) > 0 and not ts(b) = 0, ts(a) / ts(b) * 100, 0)`,
+		`retainSeries(ts(cpu.load), env=prod)`,
+		`at("end", 1, ts(cpu.load))`,
+		"hs(request.latency.m, env=prod)",
+		"events(type=alert, name=\"disk full\")",
+		"ts(${metric}, env=${env})",
+		"mavg(${window}, ts(cpu.${host}.load)) % 2",
+		"ts(cpu.load)\n  > 80",
+		"2 ^ ts(cpu.load) ^ 0.5",
+		"mavg(5m, ts(cpu.load)[1h])",
+		"rate(ts(requests))[${window}]",
+		"ts(cpu.load) as cpu",
+		"ts(cpu.load, source=web[1]) AS load",
+		"globalCount(ts(cpu.load)) + trunc(ts(a)) * sign(ts(b))",
+		`timeFmt("yyyy-MM-dd", ts(cpu.load))`,
+		"nonzero(ts(errors))",
+		"leafOnly(spans(checkout)) or rootOnly(spans(checkout))",
+		"childOf(spans(db), spans(checkout))",
+		"parentOf(spans(checkout), spans(db))",
+	}
+
+	for _, c := range cases {
+		if _, err := Parse(c); err != nil {
+			t.Errorf("expected %q to parse, got %s", c, err)
+		}
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{"", "line 1, column 1: empty query"},
+		{"ts(cpu.load", `line 1, column 12: missing ")" to close the "(" at line 1, column 3`},
+		{"ts(cpu.load))", `line 1, column 13: unexpected ")" without a matching "("`},
+		{"sume(ts(cpu.load))", `line 1, column 1: unknown function "sume", did you mean "sum"?`},
+		{"sum(ts(a)) >", "line 1, column 13: unexpected end of query, expected an expression"},
+		{"ts(a) ts(b)", `line 1, column 7: unexpected "ts" after the end of the expression`},
+		{`ts("cpu.load)`, "line 1, column 4: unterminated string"},
+		{"ts(cpu.load, env=)", `line 1, column 18: unexpected ")", expected a value for env`},
+		{"ts(cpu.load, env=prod and)", `line 1, column 26: unexpected ")", expected a filter`},
+		{"retainseriess(ts(cpu.load))", `line 1, column 1: unknown function "retainseriess", did you mean "retainSeries"?`},
+		{"ts(cpu.${env)", `line 1, column 8: unterminated parameter, expected "}"`},
+		{"mavg(5m,\n  tss(cpu.load))", `line 2, column 3: unknown function "tss", did you mean "ts"?`},
+		{"ts(a) > and 1", `line 1, column 9: unexpected "and", expected an expression`},
+		{"ts(a) # 1", `line 1, column 7: unexpected character '#'`},
+		{"ts(a) ^", "line 1, column 8: unexpected end of query, expected an expression"},
+		{"ts(a)[5]", `line 1, column 7: unexpected "5", expected a time window such as 5m`},
+		{"ts(a)[5m", `line 1, column 9: unexpected end of query, expected "]" to close the "[" at line 1, column 6`},
+		{"ts(a)]", `line 1, column 6: unexpected "]" after the end of the expression`},
+		{"ts(a) as", `line 1, column 9: unexpected end of query, expected a name after "as"`},
+		{"ts(a) as not", `line 1, column 10: unexpected "not", expected a name after "as"`},
+		{"ts(a) > as", `line 1, column 9: unexpected "as", expected an expression`},
+		{"sume(ts(a) > 1", `line 1, column 1: unknown function "sume", did you mean "sum"?`},
+	}
+
+	for _, c := range cases {
+		_, err := Parse(c.query)
+		if err == nil {
+			t.Errorf("expected %q to fail to parse", c.query)
+			continue
+		}
+		if _, ok := err.(*SyntaxError); !ok {
+			t.Errorf("expected a *SyntaxError for %q, got %T", c.query, err)
+		}
+		if err.Error() != c.expected {
+			t.Errorf("expected error '%s' for %q, got '%s'", c.expected, c.query, err)
+		}
+	}
+}
+
+func TestParse_warnings(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected []string
+	}{
+		{"ts(cpu.load)", nil},
+		{"newFunction(ts(a)) + ts(b)", []string{`line 1, column 1: unknown function "newFunction"`}},
+		{"mavg(5m,\n  spanDepthRatio(spans(checkout)))", []string{`line 2, column 3: unknown function "spanDepthRatio"`}},
+		{"${fn}(ts(a))", nil},
+	}
+
+	for _, c := range cases {
+		q, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("expected %q to parse, got %s", c.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(q.Warnings, c.expected) {
+			t.Errorf("expected warnings %q for %q, got %q", c.expected, c.query, q.Warnings)
+		}
+	}
+}
+
+func TestParse_params(t *testing.T) {
+	q, err := Parse(`sum(ts(cpu.${metric}, env=${env} and source="${env}-*"), ${group}) > ${threshold}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"env", "group", "metric", "threshold"}
+	if !reflect.DeepEqual(q.Params, expected) {
+		t.Errorf("expected params %v, got %v", expected, q.Params)
+	}
+}
+
+func TestParse_tree(t *testing.T) {
+	q, err := Parse("1 + ts(a, env=prod) * 2 > 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmp, ok := q.Root.(*BinaryExpr)
+	if !ok || cmp.Op != ">" {
+		t.Fatalf("expected a comparison at the root, got %#v", q.Root)
+	}
+	add, ok := cmp.X.(*BinaryExpr)
+	if !ok || add.Op != "+" {
+		t.Fatalf("expected an addition, got %#v", cmp.X)
+	}
+	mul, ok := add.Y.(*BinaryExpr)
+	if !ok || mul.Op != "*" {
+		t.Fatalf("expected a multiplication, got %#v", add.Y)
+	}
+	call, ok := mul.X.(*Call)
+	if !ok || call.Name != "ts" || len(call.Args) != 2 {
+		t.Fatalf("expected a ts() call with two arguments, got %#v", mul.X)
+	}
+	filter, ok := call.Args[1].(*Filter)
+	if !ok || filter.Key != "env" || filter.Op != "=" || filter.Value.Value != "prod" || filter.Pos() != 10 {
+		t.Errorf("expected the filter env=prod at offset 10, got %#v", call.Args[1])
+	}
+}
+
+func TestParse_treePowerWindowAlias(t *testing.T) {
+	q, err := Parse("2 ^ 3 ^ ts(a)[5m] as x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alias, ok := q.Root.(*Alias)
+	if !ok || alias.Name != "x" {
+		t.Fatalf("expected an alias at the root, got %#v", q.Root)
+	}
+	pow, ok := alias.X.(*BinaryExpr)
+	if !ok || pow.Op != "^" {
+		t.Fatalf("expected a power, got %#v", alias.X)
+	}
+	if base, ok := pow.X.(*Literal); !ok || base.Value != "2" {
+		t.Errorf("expected ^ to be right associative, got %#v", pow.X)
+	}
+	inner, ok := pow.Y.(*BinaryExpr)
+	if !ok || inner.Op != "^" {
+		t.Fatalf("expected a power, got %#v", pow.Y)
+	}
+	window, ok := inner.Y.(*Window)
+	if !ok || window.Duration.Value != "5m" || window.Duration.Kind != Duration || window.Pos() != 13 {
+		t.Fatalf("expected the window [5m] at offset 13, got %#v", inner.Y)
+	}
+	if call, ok := window.X.(*Call); !ok || call.Name != "ts" {
+		t.Errorf("expected the window of a ts() call, got %#v", window.X)
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	if _, err := ParseEvents("events(type=alert)"); err != nil {
+		t.Errorf("expected events(type=alert) to parse, got %s", err)
+	}
+	_, err := ParseEvents("ts(cpu.load)")
+	if err == nil || err.Error() != "line 1, column 1: expected an events() expression" {
+		t.Errorf("expected ts(cpu.load) to be rejected as an event query, got %v", err)
+	}
+}